@@ -0,0 +1,161 @@
+// Package codeowners parses CODEOWNERS files and resolves owners of paths.
+//
+// The format follows GitHub: every line is a path pattern followed by owners,
+// "#" starts a comment and the last matching rule wins. An owner is either
+// "@user_id" or "@org/team_name"; only the part after the slash is used as
+// the team name.
+package codeowners
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type Owner struct {
+	UserID   string `json:"user_id,omitempty"`
+	TeamName string `json:"team_name,omitempty"`
+}
+
+type Rule struct {
+	Pattern string  `json:"pattern"`
+	Owners  []Owner `json:"owners"`
+	Line    int     `json:"line"`
+
+	re *regexp.Regexp
+}
+
+type Ruleset struct {
+	Rules []Rule `json:"rules"`
+}
+
+type SyntaxError struct {
+	Line    int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("CODEOWNERS line %d: %s", e.Line, e.Message)
+}
+
+func Parse(content string) (*Ruleset, error) {
+	ruleset := &Ruleset{Rules: make([]Rule, 0)}
+
+	for i, line := range strings.Split(content, "\n") {
+		lineNumber := i + 1
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) == 1 {
+			return nil, &SyntaxError{Line: lineNumber, Message: fmt.Sprintf("pattern %q has no owners", fields[0])}
+		}
+
+		re, err := compilePattern(fields[0])
+		if err != nil {
+			return nil, &SyntaxError{Line: lineNumber, Message: err.Error()}
+		}
+
+		rule := Rule{Pattern: fields[0], Line: lineNumber, re: re}
+		for _, token := range fields[1:] {
+			owner, err := parseOwner(token)
+			if err != nil {
+				return nil, &SyntaxError{Line: lineNumber, Message: err.Error()}
+			}
+			rule.Owners = append(rule.Owners, owner)
+		}
+		ruleset.Rules = append(ruleset.Rules, rule)
+	}
+
+	return ruleset, nil
+}
+
+func parseOwner(token string) (Owner, error) {
+	name, ok := strings.CutPrefix(token, "@")
+	if !ok || name == "" {
+		return Owner{}, fmt.Errorf("owner %q must start with @", token)
+	}
+	if org, team, isTeam := strings.Cut(name, "/"); isTeam {
+		if org == "" || team == "" || strings.Contains(team, "/") {
+			return Owner{}, fmt.Errorf("invalid team owner %q", token)
+		}
+		return Owner{TeamName: team}, nil
+	}
+	return Owner{UserID: name}, nil
+}
+
+// compilePattern follows gitignore: only a pattern that can name a directory
+// also matches everything below it, so "docs/*" does not match "docs/a/b.md".
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	trimmed := strings.Trim(pattern, "/")
+	if trimmed == "" {
+		return nil, fmt.Errorf("invalid pattern %q", pattern)
+	}
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(trimmed, "/")
+	lastSegment := trimmed[strings.LastIndex(trimmed, "/")+1:]
+	subtree := !strings.ContainsAny(lastSegment, "*?")
+
+	var expr strings.Builder
+	if anchored {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(trimmed); i++ {
+		switch c := trimmed[i]; {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	switch {
+	case dirOnly:
+		expr.WriteString("/.*$")
+	case subtree:
+		expr.WriteString("(?:/.*)?$")
+	default:
+		expr.WriteString("$")
+	}
+	return regexp.Compile(expr.String())
+}
+
+// Owners returns the owners of the last rule matching the path.
+func (r *Ruleset) Owners(path string) []Owner {
+	path = strings.TrimPrefix(path, "/")
+	for i := len(r.Rules) - 1; i >= 0; i-- {
+		if r.Rules[i].re.MatchString(path) {
+			return r.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// OwnersOf returns the distinct owners of all paths in the order they appear.
+func (r *Ruleset) OwnersOf(paths []string) []Owner {
+	seen := make(map[Owner]bool)
+	owners := make([]Owner, 0)
+	for _, path := range paths {
+		for _, owner := range r.Owners(path) {
+			if !seen[owner] {
+				seen[owner] = true
+				owners = append(owners, owner)
+			}
+		}
+	}
+	return owners
+}
@@ -0,0 +1,133 @@
+package codeowners
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		want     []Rule
+		wantLine int
+	}{
+		{
+			name:    "users and teams",
+			content: "# owners\n\n*.go @u1 @acme/backend # go files\n/docs/ @u2\n",
+			want: []Rule{
+				{Pattern: "*.go", Line: 3, Owners: []Owner{{UserID: "u1"}, {TeamName: "backend"}}},
+				{Pattern: "/docs/", Line: 4, Owners: []Owner{{UserID: "u2"}}},
+			},
+		},
+		{name: "no owners", content: "*.go @u1\ndocs/\n", wantLine: 2},
+		{name: "owner without @", content: "*.go u1\n", wantLine: 1},
+		{name: "nested team", content: "*.go @acme/a/b\n", wantLine: 1},
+		{name: "empty pattern", content: "/ @u1\n", wantLine: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ruleset, err := Parse(tt.content)
+			if tt.wantLine != 0 {
+				var syntaxErr *SyntaxError
+				if !errors.As(err, &syntaxErr) {
+					t.Fatalf("Parse error = %v, want a SyntaxError", err)
+				}
+				if syntaxErr.Line != tt.wantLine {
+					t.Errorf("error line = %d, want %d", syntaxErr.Line, tt.wantLine)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			for i := range ruleset.Rules {
+				ruleset.Rules[i].re = nil
+			}
+			if !reflect.DeepEqual(ruleset.Rules, tt.want) {
+				t.Errorf("rules = %+v, want %+v", ruleset.Rules, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatternMatching(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "internal/service/pr.go", true},
+		{"*.go", "main.go.txt", false},
+		{"docs/*", "docs/guide.md", true},
+		{"docs/*", "docs/build/guide.md", false},
+		{"docs/*", "src/docs/guide.md", false},
+		{"docs", "docs/build/guide.md", true},
+		{"docs", "src/docs/guide.md", true},
+		{"docs/", "docs/guide.md", true},
+		{"docs/", "docs", false},
+		{"/docs/", "src/docs/guide.md", false},
+		{"/build/logs", "build/logs/today.log", true},
+		{"/build/logs", "src/build/logs/today.log", false},
+		{"apps/**", "apps/web/src/index.js", true},
+		{"apps/**", "lib/apps/index.js", false},
+		{"**/logs", "deploy/build/logs/today.log", true},
+		{"**/logs", "logs/today.log", true},
+		{"src/**/test.go", "src/test.go", true},
+		{"src/**/test.go", "src/a/b/test.go", true},
+		{"src/**/test.go", "src/a/b/test.go.orig", false},
+		{"?.md", "a.md", true},
+		{"?.md", "ab.md", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			re, err := compilePattern(tt.pattern)
+			if err != nil {
+				t.Fatalf("compilePattern: %v", err)
+			}
+			if got := re.MatchString(tt.path); got != tt.want {
+				t.Errorf("match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOwners(t *testing.T) {
+	ruleset, err := Parse(`
+* @u1
+*.go @u2
+/internal/ @acme/backend
+/internal/metrics/ @u3
+`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want []Owner
+	}{
+		{"README.md", []Owner{{UserID: "u1"}}},
+		{"cmd/server/main.go", []Owner{{UserID: "u2"}}},
+		{"/internal/service/pr.go", []Owner{{TeamName: "backend"}}},
+		{"internal/metrics/metrics.go", []Owner{{UserID: "u3"}}},
+	}
+	for _, tt := range tests {
+		if got := ruleset.Owners(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Owners(%q) = %+v, want %+v (the last matching rule wins)", tt.path, got, tt.want)
+		}
+	}
+
+	got := ruleset.OwnersOf([]string{"internal/a.go", "b.go", "internal/c.go", "d.go"})
+	want := []Owner{{TeamName: "backend"}, {UserID: "u2"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("OwnersOf = %+v, want %+v", got, want)
+	}
+	if got := (&Ruleset{}).Owners("main.go"); got != nil {
+		t.Errorf("Owners with no rules = %+v, want nil", got)
+	}
+}
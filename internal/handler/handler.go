@@ -1,11 +1,19 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"pr-reviewer-service/internal/codeowners"
+	"pr-reviewer-service/internal/integration/github"
+	"pr-reviewer-service/internal/integration/gitlab"
 	"pr-reviewer-service/internal/models"
 	"pr-reviewer-service/internal/repository"
 	"pr-reviewer-service/internal/service"
@@ -17,6 +25,12 @@ type Handler struct {
 	prService           *service.PullRequestService
 	statsService        *service.StatsService
 	deactivationService *service.DeactivationService
+	reactivationService *service.ReactivationService
+	availabilityService *service.AvailabilityService
+	historyService      *service.HistoryService
+	webhookService      *service.WebhookService
+	integrationService  *service.IntegrationService
+	healthService       *service.HealthService
 }
 
 func NewHandler(
@@ -25,6 +39,12 @@ func NewHandler(
 	prService *service.PullRequestService,
 	statsService *service.StatsService,
 	deactivationService *service.DeactivationService,
+	reactivationService *service.ReactivationService,
+	availabilityService *service.AvailabilityService,
+	historyService *service.HistoryService,
+	webhookService *service.WebhookService,
+	integrationService *service.IntegrationService,
+	healthService *service.HealthService,
 ) *Handler {
 	return &Handler{
 		teamService:         teamService,
@@ -32,16 +52,37 @@ func NewHandler(
 		prService:           prService,
 		statsService:        statsService,
 		deactivationService: deactivationService,
+		reactivationService: reactivationService,
+		availabilityService: availabilityService,
+		historyService:      historyService,
+		webhookService:      webhookService,
+		integrationService:  integrationService,
+		healthService:       healthService,
 	}
 }
 
 const (
-	ErrorCodeTeamExists   = "TEAM_EXISTS"
-	ErrorCodePRExists     = "PR_EXISTS"
-	ErrorCodePRMerged     = "PR_MERGED"
-	ErrorCodeNotAssigned  = "NOT_ASSIGNED"
-	ErrorCodeNoCandidate  = "NO_CANDIDATE"
-	ErrorCodeNotFound     = "NOT_FOUND"
+	ErrorCodeTeamExists         = "TEAM_EXISTS"
+	ErrorCodePRExists           = "PR_EXISTS"
+	ErrorCodePRMerged           = "PR_MERGED"
+	ErrorCodeNotAssigned        = "NOT_ASSIGNED"
+	ErrorCodeNoCandidate        = "NO_CANDIDATE"
+	ErrorCodeNotFound           = "NOT_FOUND"
+	ErrorCodeNotEnoughReviewers = "NOT_ENOUGH_REVIEWERS"
+	ErrorCodeAllAtCapacity      = "ALL_AT_CAPACITY"
+	ErrorCodePRClosed           = "PR_CLOSED"
+	ErrorCodePRDraft            = "PR_DRAFT"
+	ErrorCodeInvalidTransition  = "INVALID_TRANSITION"
+	ErrorCodeNotApproved        = "NOT_APPROVED"
+	ErrorCodeInvalidSignature   = "INVALID_SIGNATURE"
+	ErrorCodeNotConfigured      = "NOT_CONFIGURED"
+	ErrorCodeReassignmentFailed = "REASSIGNMENT_FAILED"
+	ErrorCodeTimeout            = "TIMEOUT"
+)
+
+const (
+	ActorHeader  = "X-Actor"
+	DefaultActor = "anonymous"
 )
 
 type ErrorResponse struct {
@@ -64,6 +105,41 @@ func (h *Handler) writeError(w http.ResponseWriter, code string, message string,
 	})
 }
 
+// writeInternalError reports an error the request cannot be blamed for. A
+// database query that ran out of time is reported as TIMEOUT.
+func (h *Handler) writeInternalError(w http.ResponseWriter, err error) {
+	if errors.Is(err, repository.ErrTimeout) {
+		h.writeError(w, ErrorCodeTimeout, "database query timed out", http.StatusGatewayTimeout)
+		return
+	}
+	h.writeError(w, ErrorCodeNotFound, err.Error(), http.StatusInternalServerError)
+}
+
+// writeStatusError handles errors of operations forbidden in the current PR status.
+func (h *Handler) writeStatusError(w http.ResponseWriter, err error) bool {
+	switch err {
+	case service.ErrPRMerged:
+		h.writeError(w, ErrorCodePRMerged, "PR is already merged", http.StatusConflict)
+	case service.ErrPRClosed:
+		h.writeError(w, ErrorCodePRClosed, "PR is closed", http.StatusConflict)
+	case service.ErrPRDraft:
+		h.writeError(w, ErrorCodePRDraft, "PR is a draft", http.StatusConflict)
+	case service.ErrInvalidTransition:
+		h.writeError(w, ErrorCodeInvalidTransition, "PR status transition is not allowed", http.StatusConflict)
+	default:
+		return false
+	}
+	return true
+}
+
+// actor identifies the caller for the audit log.
+func (h *Handler) actor(r *http.Request) string {
+	if actor := strings.TrimSpace(r.Header.Get(ActorHeader)); actor != "" {
+		return actor
+	}
+	return DefaultActor
+}
+
 func (h *Handler) checkMethod(w http.ResponseWriter, r *http.Request, method string) bool {
 	if r.Method != method {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -106,19 +182,19 @@ func (h *Handler) AddTeam(w http.ResponseWriter, r *http.Request) {
 		Members:  req.Members,
 	}
 
-	err := h.teamService.CreateTeam(team)
+	err := h.teamService.CreateTeam(r.Context(), team)
 	if err != nil {
 		if err == repository.ErrTeamExists {
 			h.writeError(w, ErrorCodeTeamExists, "team_name already exists", http.StatusBadRequest)
 			return
 		}
-		h.writeError(w, ErrorCodeNotFound, err.Error(), http.StatusInternalServerError)
+		h.writeInternalError(w, err)
 		return
 	}
 
-	createdTeam, err := h.teamService.GetTeam(req.TeamName)
+	createdTeam, err := h.teamService.GetTeam(r.Context(), req.TeamName)
 	if err != nil {
-		h.writeError(w, ErrorCodeNotFound, err.Error(), http.StatusInternalServerError)
+		h.writeInternalError(w, err)
 		return
 	}
 
@@ -140,13 +216,13 @@ func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	team, err := h.teamService.GetTeam(teamName)
+	team, err := h.teamService.GetTeam(r.Context(), teamName)
 	if err != nil {
 		if err == repository.ErrTeamNotFound {
 			h.writeError(w, ErrorCodeNotFound, "team not found", http.StatusNotFound)
 			return
 		}
-		h.writeError(w, ErrorCodeNotFound, err.Error(), http.StatusInternalServerError)
+		h.writeInternalError(w, err)
 		return
 	}
 
@@ -154,14 +230,46 @@ func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(team)
 }
 
-func (h *Handler) SetIsActive(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) GetTeamSettings(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		h.writeError(w, ErrorCodeNotFound, "team_name is required", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.teamService.GetSettings(r.Context(), teamName)
+	if err != nil {
+		if err == repository.ErrTeamNotFound {
+			h.writeError(w, ErrorCodeNotFound, "team not found", http.StatusNotFound)
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"settings": settings,
+	})
+}
+
+func (h *Handler) UpdateTeamSettings(w http.ResponseWriter, r *http.Request) {
 	if !h.checkMethod(w, r, http.MethodPost) {
 		return
 	}
-	
+
 	var req struct {
-		UserID   string `json:"user_id"`
-		IsActive bool   `json:"is_active"`
+		TeamName          string    `json:"team_name"`
+		ReviewerCount     *int      `json:"reviewer_count"`
+		MinReviewers      *int      `json:"min_reviewers"`
+		AllowSelfReview   *bool     `json:"allow_self_review"`
+		CapacityPolicy    *string   `json:"capacity_policy"`
+		RequiredApprovals *int      `json:"required_approvals"`
+		FallbackTeams     *[]string `json:"fallback_teams"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -169,31 +277,105 @@ func (h *Handler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.userService.SetIsActive(req.UserID, req.IsActive)
+	if req.TeamName == "" {
+		h.writeError(w, ErrorCodeNotFound, "team_name is required", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.teamService.GetSettings(r.Context(), req.TeamName)
 	if err != nil {
-		if err == repository.ErrUserNotFound {
-			h.writeError(w, ErrorCodeNotFound, "user not found", http.StatusNotFound)
+		if err == repository.ErrTeamNotFound {
+			h.writeError(w, ErrorCodeNotFound, "team not found", http.StatusNotFound)
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	if req.ReviewerCount != nil {
+		settings.ReviewerCount = *req.ReviewerCount
+	}
+	if req.MinReviewers != nil {
+		settings.MinReviewers = *req.MinReviewers
+	}
+	if req.AllowSelfReview != nil {
+		settings.AllowSelfReview = *req.AllowSelfReview
+	}
+	if req.CapacityPolicy != nil {
+		settings.CapacityPolicy = *req.CapacityPolicy
+	}
+	if req.RequiredApprovals != nil {
+		settings.RequiredApprovals = *req.RequiredApprovals
+	}
+	if req.FallbackTeams != nil {
+		settings.FallbackTeams = *req.FallbackTeams
+	}
+
+	updated, err := h.teamService.UpdateSettings(r.Context(), settings)
+	if err != nil {
+		if err == service.ErrInvalidSettings {
+			h.writeError(w, ErrorCodeNotFound, fmt.Sprintf(
+				"reviewer_count must be within 0..%d, min_reviewers within 0..reviewer_count, "+
+					"capacity_policy one of %q, %q, required_approvals within 0..reviewer_count",
+				models.MaxReviewerCount, models.CapacityPolicyAssignFewer, models.CapacityPolicyFail), http.StatusBadRequest)
+			return
+		}
+		if err == service.ErrInvalidFallbackTeams {
+			h.writeError(w, ErrorCodeNotFound,
+				"fallback_teams must list existing teams other than the team itself, without duplicates",
+				http.StatusBadRequest)
+			return
+		}
+		if err == repository.ErrTeamNotFound {
+			h.writeError(w, ErrorCodeNotFound, "team not found", http.StatusNotFound)
 			return
 		}
-		h.writeError(w, ErrorCodeNotFound, err.Error(), http.StatusInternalServerError)
+		h.writeInternalError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"user": user,
+		"settings": updated,
 	})
 }
 
-func (h *Handler) CreatePullRequest(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) GetTeamCodeOwners(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		h.writeError(w, ErrorCodeNotFound, "team_name is required", http.StatusBadRequest)
+		return
+	}
+
+	codeOwners, ruleset, err := h.teamService.GetCodeOwners(r.Context(), teamName)
+	if err != nil {
+		if err == repository.ErrTeamNotFound {
+			h.writeError(w, ErrorCodeNotFound, "team not found", http.StatusNotFound)
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"codeowners": codeOwners,
+		"rules":      ruleset.Rules,
+	})
+}
+
+func (h *Handler) UpdateTeamCodeOwners(w http.ResponseWriter, r *http.Request) {
 	if !h.checkMethod(w, r, http.MethodPost) {
 		return
 	}
-	
+
 	var req struct {
-		PullRequestID   string `json:"pull_request_id"`
-		PullRequestName string `json:"pull_request_name"`
-		AuthorID        string `json:"author_id"`
+		TeamName string `json:"team_name"`
+		Content  string `json:"content"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -201,34 +383,41 @@ func (h *Handler) CreatePullRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, err := h.prService.CreatePR(req.PullRequestID, req.PullRequestName, req.AuthorID)
+	if req.TeamName == "" {
+		h.writeError(w, ErrorCodeNotFound, "team_name is required", http.StatusBadRequest)
+		return
+	}
+
+	codeOwners, ruleset, err := h.teamService.SetCodeOwners(r.Context(), req.TeamName, req.Content)
 	if err != nil {
-		if err == service.ErrAuthorNotFound || err == service.ErrTeamNotFound {
-			h.writeError(w, ErrorCodeNotFound, "author or team not found", http.StatusNotFound)
+		var syntaxErr *codeowners.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			h.writeError(w, ErrorCodeNotFound, syntaxErr.Error(), http.StatusBadRequest)
 			return
 		}
-		if err == repository.ErrPRExists {
-			h.writeError(w, ErrorCodePRExists, "PR id already exists", http.StatusConflict)
+		if err == repository.ErrTeamNotFound {
+			h.writeError(w, ErrorCodeNotFound, "team not found", http.StatusNotFound)
 			return
 		}
-		h.writeError(w, ErrorCodeNotFound, err.Error(), http.StatusInternalServerError)
+		h.writeInternalError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"pr": pr,
+		"codeowners": codeOwners,
+		"rules":      ruleset.Rules,
 	})
 }
 
-func (h *Handler) MergePullRequest(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 	if !h.checkMethod(w, r, http.MethodPost) {
 		return
 	}
 	
 	var req struct {
-		PullRequestID string `json:"pull_request_id"`
+		UserID   string `json:"user_id"`
+		IsActive bool   `json:"is_active"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -236,30 +425,30 @@ func (h *Handler) MergePullRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, err := h.prService.MergePR(req.PullRequestID)
+	user, err := h.userService.SetIsActive(r.Context(), req.UserID, req.IsActive)
 	if err != nil {
-		if err == repository.ErrPRNotFound {
-			h.writeError(w, ErrorCodeNotFound, "PR not found", http.StatusNotFound)
+		if err == repository.ErrUserNotFound {
+			h.writeError(w, ErrorCodeNotFound, "user not found", http.StatusNotFound)
 			return
 		}
-		h.writeError(w, ErrorCodeNotFound, err.Error(), http.StatusInternalServerError)
+		h.writeInternalError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"pr": pr,
+		"user": user,
 	})
 }
 
-func (h *Handler) ReassignPullRequest(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) SetMaxOpenReviews(w http.ResponseWriter, r *http.Request) {
 	if !h.checkMethod(w, r, http.MethodPost) {
 		return
 	}
-	
+
 	var req struct {
-		PullRequestID string `json:"pull_request_id"`
-		OldUserID     string `json:"old_user_id"`
+		UserID         string `json:"user_id"`
+		MaxOpenReviews *int   `json:"max_open_reviews"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -267,123 +456,1191 @@ func (h *Handler) ReassignPullRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, newUserID, err := h.prService.ReassignReviewer(req.PullRequestID, req.OldUserID)
+	user, err := h.userService.SetMaxOpenReviews(r.Context(), req.UserID, req.MaxOpenReviews)
 	if err != nil {
-		if err == repository.ErrPRNotFound || err == repository.ErrUserNotFound {
-			h.writeError(w, ErrorCodeNotFound, "PR or user not found", http.StatusNotFound)
-			return
-		}
-		if err == service.ErrPRMerged {
-			h.writeError(w, ErrorCodePRMerged, "cannot reassign on merged PR", http.StatusConflict)
-			return
-		}
-		if err == service.ErrReviewerNotAssigned {
-			h.writeError(w, ErrorCodeNotAssigned, "reviewer is not assigned to this PR", http.StatusConflict)
-			return
-		}
-		if err == service.ErrNoCandidate {
-			h.writeError(w, ErrorCodeNoCandidate, "no active replacement candidate in team", http.StatusConflict)
+		if err == service.ErrInvalidCapacity {
+			h.writeError(w, ErrorCodeNotFound, err.Error(), http.StatusBadRequest)
 			return
 		}
-		if strings.Contains(err.Error(), "reviewer is not assigned") {
-			h.writeError(w, ErrorCodeNotAssigned, "reviewer is not assigned to this PR", http.StatusConflict)
+		if err == repository.ErrUserNotFound {
+			h.writeError(w, ErrorCodeNotFound, "user not found", http.StatusNotFound)
 			return
 		}
-		h.writeError(w, ErrorCodeNotFound, err.Error(), http.StatusInternalServerError)
+		h.writeInternalError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"pr":          pr,
-		"replaced_by": newUserID,
+		"user": user,
 	})
 }
 
-func (h *Handler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
-	if !h.checkMethod(w, r, http.MethodGet) {
+func (h *Handler) CreatePullRequest(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodPost) {
 		return
 	}
 	
-	userID := r.URL.Query().Get("user_id")
-	if userID == "" {
-		h.writeError(w, ErrorCodeNotFound, "user_id is required", http.StatusBadRequest)
+	var req struct {
+		PullRequestID   string `json:"pull_request_id"`
+		PullRequestName string `json:"pull_request_name"`
+		AuthorID        string   `json:"author_id"`
+		Draft           bool     `json:"draft"`
+		ChangedFiles    []string `json:"changed_files"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	prs, err := h.prService.GetPRsByReviewer(userID)
-	if err != nil {
-		if err == repository.ErrUserNotFound {
-			h.writeError(w, ErrorCodeNotFound, "user not found", http.StatusNotFound)
+	for _, path := range req.ChangedFiles {
+		if strings.TrimSpace(path) == "" {
+			h.writeError(w, ErrorCodeNotFound, "changed_files must not contain empty paths", http.StatusBadRequest)
 			return
 		}
-		h.writeError(w, ErrorCodeNotFound, err.Error(), http.StatusInternalServerError)
+	}
+
+	var pr *models.PullRequest
+	var explanation *models.Explanation
+	create := func(prService *service.PullRequestService) error {
+		var err error
+		if explainRequested(r) {
+			pr, explanation, err = prService.CreatePRExplained(
+				r.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID, req.Draft, req.ChangedFiles, h.actor(r))
+		} else {
+			pr, err = prService.CreatePR(r.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID, req.Draft, req.ChangedFiles, h.actor(r))
+		}
+		return err
+	}
+
+	dryRun := dryRunRequested(r)
+	var err error
+	if dryRun {
+		err = h.prService.DryRun(r.Context(), create)
+	} else {
+		err = create(h.prService)
+	}
+	if err != nil {
+		h.writeCreateError(w, err)
 		return
 	}
 
+	response := map[string]interface{}{
+		"pr": pr,
+	}
+	if explanation != nil {
+		response["explanation"] = explanation
+	}
+
+	status := http.StatusCreated
+	if dryRun {
+		response["dry_run"] = true
+		status = http.StatusOK
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"user_id":      userID,
-		"pull_requests": prs,
-	})
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
 }
 
-func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
-	if !h.checkMethod(w, r, http.MethodGet) {
+func (h *Handler) writeCreateError(w http.ResponseWriter, err error) {
+	if err == service.ErrAuthorNotFound || err == service.ErrTeamNotFound {
+		h.writeError(w, ErrorCodeNotFound, "author or team not found", http.StatusNotFound)
 		return
 	}
-	
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
-		"status": "ok",
-	})
+	if err == repository.ErrPRExists {
+		h.writeError(w, ErrorCodePRExists, "PR id already exists", http.StatusConflict)
+		return
+	}
+	if err == service.ErrNotEnoughReviewers {
+		h.writeError(w, ErrorCodeNotEnoughReviewers, "not enough active reviewers to satisfy team policy", http.StatusConflict)
+		return
+	}
+	if err == service.ErrAllAtCapacity {
+		h.writeError(w, ErrorCodeAllAtCapacity, "all candidates are at review capacity", http.StatusConflict)
+		return
+	}
+	h.writeInternalError(w, err)
 }
 
-func (h *Handler) GetStatistics(w http.ResponseWriter, r *http.Request) {
-	if !h.checkMethod(w, r, http.MethodGet) {
+// explainRequested reports whether the caller asked for ?explain=true.
+func explainRequested(r *http.Request) bool {
+	return r.URL.Query().Get("explain") == "true"
+}
+
+// dryRunRequested reports whether the caller asked for ?dry_run=true, i.e. to
+// see the result of the operation without applying it.
+func dryRunRequested(r *http.Request) bool {
+	return r.URL.Query().Get("dry_run") == "true"
+}
+
+func (h *Handler) MergePullRequest(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodPost) {
 		return
 	}
 	
-	stats, err := h.statsService.GetStatistics()
+	var req struct {
+		PullRequestID string `json:"pull_request_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	pr, err := h.prService.MergePR(r.Context(), req.PullRequestID, h.actor(r))
 	if err != nil {
-		h.writeError(w, ErrorCodeNotFound, err.Error(), http.StatusInternalServerError)
+		if err == repository.ErrPRNotFound {
+			h.writeError(w, ErrorCodeNotFound, "PR not found", http.StatusNotFound)
+			return
+		}
+		if err == service.ErrNotApproved {
+			h.writeError(w, ErrorCodeNotApproved, "PR does not have enough approvals", http.StatusConflict)
+			return
+		}
+		if err == service.ErrChangesRequested {
+			h.writeError(w, ErrorCodeNotApproved, "PR has outstanding change requests", http.StatusConflict)
+			return
+		}
+		if h.writeStatusError(w, err) {
+			return
+		}
+		h.writeInternalError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"statistics": stats,
+		"pr": pr,
 	})
 }
 
-func (h *Handler) DeactivateUsers(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) ReviewPullRequest(w http.ResponseWriter, r *http.Request) {
 	if !h.checkMethod(w, r, http.MethodPost) {
 		return
 	}
-	
-	var req models.DeactivationRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
-		return
+	var req struct {
+		PullRequestID string               `json:"pull_request_id"`
+		UserID        string               `json:"user_id"`
+		Verdict       models.ReviewVerdict `json:"verdict"`
 	}
 
-	if req.TeamName == "" {
-		h.writeError(w, ErrorCodeNotFound, "team_name is required", http.StatusBadRequest)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	response, err := h.deactivationService.DeactivateUsers(req.TeamName, req.UserIDs)
+	pr, err := h.prService.SubmitReview(r.Context(), req.PullRequestID, req.UserID, req.Verdict)
 	if err != nil {
-		if err == repository.ErrTeamNotFound {
-			h.writeError(w, ErrorCodeNotFound, "team not found", http.StatusNotFound)
+		if err == service.ErrInvalidVerdict {
+			h.writeError(w, ErrorCodeNotFound, "verdict must be one of APPROVED, CHANGES_REQUESTED, COMMENTED", http.StatusBadRequest)
+			return
+		}
+		if err == repository.ErrPRNotFound {
+			h.writeError(w, ErrorCodeNotFound, "PR not found", http.StatusNotFound)
+			return
+		}
+		if err == service.ErrReviewerNotAssigned {
+			h.writeError(w, ErrorCodeNotAssigned, "reviewer is not assigned to this PR", http.StatusConflict)
+			return
+		}
+		if h.writeStatusError(w, err) {
 			return
 		}
-		h.writeError(w, ErrorCodeNotFound, err.Error(), http.StatusInternalServerError)
+		h.writeInternalError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"pr": pr,
+	})
+}
+
+func (h *Handler) ClosePullRequest(w http.ResponseWriter, r *http.Request) {
+	h.changePullRequestStatus(w, r, h.prService.ClosePR)
+}
+
+func (h *Handler) ReopenPullRequest(w http.ResponseWriter, r *http.Request) {
+	h.changePullRequestStatus(w, r, h.prService.ReopenPR)
 }
 
+func (h *Handler) MarkPullRequestReady(w http.ResponseWriter, r *http.Request) {
+	h.changePullRequestStatus(w, r, h.prService.MarkReady)
+}
+
+func (h *Handler) changePullRequestStatus(
+	w http.ResponseWriter,
+	r *http.Request,
+	change func(ctx context.Context, prID, actor string) (*models.PullRequest, error),
+) {
+	if !h.checkMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req struct {
+		PullRequestID string `json:"pull_request_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	pr, err := change(r.Context(), req.PullRequestID, h.actor(r))
+	if err != nil {
+		if err == repository.ErrPRNotFound {
+			h.writeError(w, ErrorCodeNotFound, "PR not found", http.StatusNotFound)
+			return
+		}
+		if err == service.ErrAuthorNotFound || err == service.ErrTeamNotFound {
+			h.writeError(w, ErrorCodeNotFound, "author or team not found", http.StatusNotFound)
+			return
+		}
+		if err == service.ErrNotEnoughReviewers {
+			h.writeError(w, ErrorCodeNotEnoughReviewers, "not enough active reviewers to satisfy team policy", http.StatusConflict)
+			return
+		}
+		if err == service.ErrAllAtCapacity {
+			h.writeError(w, ErrorCodeAllAtCapacity, "all candidates are at review capacity", http.StatusConflict)
+			return
+		}
+		if h.writeStatusError(w, err) {
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"pr": pr,
+	})
+}
+
+func (h *Handler) ReassignPullRequest(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodPost) {
+		return
+	}
+	
+	var req struct {
+		PullRequestID string `json:"pull_request_id"`
+		OldUserID     string `json:"old_user_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var pr *models.PullRequest
+	var newUserID string
+	var explanation *models.Explanation
+	reassign := func(prService *service.PullRequestService) error {
+		var err error
+		if explainRequested(r) {
+			pr, newUserID, explanation, err = prService.ReassignReviewerExplained(r.Context(), req.PullRequestID, req.OldUserID, h.actor(r))
+		} else {
+			pr, newUserID, err = prService.ReassignReviewer(r.Context(), req.PullRequestID, req.OldUserID, h.actor(r))
+		}
+		return err
+	}
+
+	dryRun := dryRunRequested(r)
+	var err error
+	if dryRun {
+		err = h.prService.DryRun(r.Context(), reassign)
+	} else {
+		err = reassign(h.prService)
+	}
+	if err != nil {
+		h.writeReassignError(w, err)
+		return
+	}
+
+	response := map[string]interface{}{
+		"pr":          pr,
+		"replaced_by": newUserID,
+	}
+	if explanation != nil {
+		response["explanation"] = explanation
+	}
+	if dryRun {
+		response["dry_run"] = true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) writeReassignError(w http.ResponseWriter, err error) {
+	if err == repository.ErrPRNotFound || err == repository.ErrUserNotFound {
+		h.writeError(w, ErrorCodeNotFound, "PR or user not found", http.StatusNotFound)
+		return
+	}
+	if err == service.ErrPRMerged {
+		h.writeError(w, ErrorCodePRMerged, "cannot reassign on merged PR", http.StatusConflict)
+		return
+	}
+	if h.writeStatusError(w, err) {
+		return
+	}
+	if err == service.ErrReviewerNotAssigned {
+		h.writeError(w, ErrorCodeNotAssigned, "reviewer is not assigned to this PR", http.StatusConflict)
+		return
+	}
+	if err == service.ErrNoCandidate {
+		h.writeError(w, ErrorCodeNoCandidate, "no active replacement candidate in team", http.StatusConflict)
+		return
+	}
+	if err == service.ErrAllAtCapacity {
+		h.writeError(w, ErrorCodeAllAtCapacity, "all replacement candidates are at review capacity", http.StatusConflict)
+		return
+	}
+	if strings.Contains(err.Error(), "reviewer is not assigned") {
+		h.writeError(w, ErrorCodeNotAssigned, "reviewer is not assigned to this PR", http.StatusConflict)
+		return
+	}
+	h.writeInternalError(w, err)
+}
+
+// ExplainPullRequest shows how reviewers would be selected right now without
+// changing anything: for a new PR of author_id, or, with old_user_id, for
+// replacing that reviewer on pull_request_id.
+func (h *Handler) ExplainPullRequest(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req struct {
+		PullRequestID string   `json:"pull_request_id"`
+		AuthorID      string   `json:"author_id"`
+		ChangedFiles  []string `json:"changed_files"`
+		OldUserID     string   `json:"old_user_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var explanation *models.Explanation
+	var err error
+	if req.OldUserID != "" {
+		if req.PullRequestID == "" {
+			h.writeError(w, ErrorCodeNotFound, "pull_request_id is required with old_user_id", http.StatusBadRequest)
+			return
+		}
+		explanation, err = h.prService.ExplainReassign(r.Context(), req.PullRequestID, req.OldUserID)
+		if err != nil {
+			h.writeReassignError(w, err)
+			return
+		}
+	} else {
+		if req.AuthorID == "" {
+			h.writeError(w, ErrorCodeNotFound, "author_id or old_user_id is required", http.StatusBadRequest)
+			return
+		}
+		explanation, err = h.prService.ExplainCreate(r.Context(), req.AuthorID, req.ChangedFiles)
+		if err != nil {
+			h.writeCreateError(w, err)
+			return
+		}
+		explanation.PullRequestID = req.PullRequestID
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"explanation": explanation,
+	})
+}
+
+func (h *Handler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodGet) {
+		return
+	}
+	
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		h.writeError(w, ErrorCodeNotFound, "user_id is required", http.StatusBadRequest)
+		return
+	}
+
+	prs, err := h.prService.GetPRsByReviewer(r.Context(), userID)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			h.writeError(w, ErrorCodeNotFound, "user not found", http.StatusNotFound)
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"user_id":      userID,
+		"pull_requests": prs,
+	})
+}
+
+func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodGet) {
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"status": "ok",
+	})
+}
+
+// Livez reports that the process is up and serving requests. It checks no
+// dependencies, so a failing database does not get the instance restarted.
+func (h *Handler) Livez(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"status": "ok",
+	})
+}
+
+// Readyz reports whether the instance can serve traffic. Any failed check
+// answers 503, so that the instance is taken out of the load balancer.
+func (h *Handler) Readyz(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	readiness := h.healthService.Readiness(r.Context())
+
+	status := http.StatusOK
+	if readiness.Status != models.CheckOK {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(readiness)
+}
+
+func (h *Handler) GetStatistics(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodGet) {
+		return
+	}
+	
+	filter := models.StatsFilter{TeamName: r.URL.Query().Get("team_name")}
+	for _, bound := range []struct {
+		name string
+		dest *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		value := r.URL.Query().Get(bound.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			h.writeError(w, ErrorCodeNotFound, bound.name+" must be an RFC 3339 time", http.StatusBadRequest)
+			return
+		}
+		*bound.dest = t
+	}
+
+	stats, err := h.statsService.GetStatistics(r.Context(), filter)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidPeriod:
+			h.writeError(w, ErrorCodeNotFound, err.Error(), http.StatusBadRequest)
+		case service.ErrTeamNotFound:
+			h.writeError(w, ErrorCodeNotFound, "team not found", http.StatusNotFound)
+		default:
+			h.writeInternalError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+func (h *Handler) DeactivateUsers(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodPost) {
+		return
+	}
+	
+	var req models.DeactivationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.TeamName == "" {
+		h.writeError(w, ErrorCodeNotFound, "team_name is required", http.StatusBadRequest)
+		return
+	}
+
+	var response *models.DeactivationResponse
+	var err error
+	switch {
+	case dryRunRequested(r):
+		response, err = h.deactivationService.DeactivateUsersDryRun(r.Context(), req.TeamName, req.UserIDs, h.actor(r), req.Atomic)
+	case req.Atomic:
+		response, err = h.deactivationService.DeactivateUsersAtomic(r.Context(), req.TeamName, req.UserIDs, h.actor(r))
+	default:
+		response, err = h.deactivationService.DeactivateUsers(r.Context(), req.TeamName, req.UserIDs, h.actor(r))
+	}
+	if err != nil {
+		if err == service.ErrReassignmentFailed {
+			// The report lists every PR, so the caller sees which ones blocked the deactivation.
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": ErrorDetail{
+					Code:    ErrorCodeReassignmentFailed,
+					Message: "some open reviews cannot be reassigned, nothing was changed",
+				},
+				"report": response,
+			})
+			return
+		}
+		if errors.Is(err, repository.ErrTeamNotFound) {
+			h.writeError(w, ErrorCodeNotFound, "team not found", http.StatusNotFound)
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if response.Job != nil && response.Job.Status == models.JobRunning {
+		status = http.StatusAccepted
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) ReactivateUser(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req models.ReactivationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.UserID == "" {
+		h.writeError(w, ErrorCodeNotFound, "user_id is required", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.reactivationService.ReactivateUser(r.Context(), &req, h.actor(r))
+	if err != nil {
+		switch err {
+		case service.ErrInvalidLimit:
+			h.writeError(w, ErrorCodeNotFound, err.Error(), http.StatusBadRequest)
+		case repository.ErrUserNotFound:
+			h.writeError(w, ErrorCodeNotFound, "user not found", http.StatusNotFound)
+		default:
+			h.writeInternalError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) GetJob(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	jobID, err := strconv.ParseInt(r.URL.Query().Get("job_id"), 10, 64)
+	if err != nil {
+		h.writeError(w, ErrorCodeNotFound, "job_id must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.deactivationService.GetJob(r.Context(), jobID)
+	if err != nil {
+		if err == repository.ErrJobNotFound {
+			h.writeError(w, ErrorCodeNotFound, "job not found", http.StatusNotFound)
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"job": job,
+	})
+}
+
+func (h *Handler) RetryJob(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req struct {
+		JobID int64 `json:"job_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.deactivationService.RetryJob(r.Context(), req.JobID)
+	if err != nil {
+		if err == repository.ErrJobNotFound {
+			h.writeError(w, ErrorCodeNotFound, "job with failed items not found", http.StatusNotFound)
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"job": job,
+	})
+}
+
+
+func (h *Handler) GetAvailability(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		h.writeError(w, ErrorCodeNotFound, "user_id is required", http.StatusBadRequest)
+		return
+	}
+
+	windows, err := h.availabilityService.GetAvailability(r.Context(), userID)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			h.writeError(w, ErrorCodeNotFound, "user not found", http.StatusNotFound)
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"user_id":      userID,
+		"availability": windows,
+	})
+}
+
+func (h *Handler) AddAvailability(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req struct {
+		UserID          string    `json:"user_id"`
+		StartsAt        time.Time `json:"starts_at"`
+		EndsAt          time.Time `json:"ends_at"`
+		Reason          string    `json:"reason"`
+		ReassignReviews bool      `json:"reassign_reviews"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.UserID == "" {
+		h.writeError(w, ErrorCodeNotFound, "user_id is required", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.availabilityService.AddAvailability(r.Context(), &models.Availability{
+		UserID:          req.UserID,
+		StartsAt:        req.StartsAt,
+		EndsAt:          req.EndsAt,
+		Reason:          req.Reason,
+		ReassignReviews: req.ReassignReviews,
+	})
+	if err != nil {
+		if err == service.ErrInvalidAvailability {
+			h.writeError(w, ErrorCodeNotFound, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err == repository.ErrUserNotFound {
+			h.writeError(w, ErrorCodeNotFound, "user not found", http.StatusNotFound)
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) DeleteAvailability(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req struct {
+		AvailabilityID int64 `json:"availability_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err := h.availabilityService.DeleteAvailability(r.Context(), req.AvailabilityID)
+	if err != nil {
+		if err == repository.ErrAvailabilityNotFound {
+			h.writeError(w, ErrorCodeNotFound, "availability window not found", http.StatusNotFound)
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"availability_id": req.AvailabilityID,
+	})
+}
+
+func (h *Handler) GetPullRequestHistory(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		h.writeError(w, ErrorCodeNotFound, "pull_request_id is required", http.StatusBadRequest)
+		return
+	}
+
+	events, err := h.historyService.GetPRHistory(r.Context(), prID)
+	if err != nil {
+		if err == repository.ErrPRNotFound {
+			h.writeError(w, ErrorCodeNotFound, "PR not found", http.StatusNotFound)
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"pull_request_id": prID,
+		"events":          events,
+	})
+}
+
+func (h *Handler) GetUserHistory(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		h.writeError(w, ErrorCodeNotFound, "user_id is required", http.StatusBadRequest)
+		return
+	}
+
+	events, err := h.historyService.GetUserHistory(r.Context(), userID)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			h.writeError(w, ErrorCodeNotFound, "user not found", http.StatusNotFound)
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"user_id": userID,
+		"events":  events,
+	})
+}
+
+func (h *Handler) AddWebhook(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req struct {
+		URL    string   `json:"url"`
+		Events []string `json:"events"`
+		Secret string   `json:"secret"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	sub, err := h.webhookService.CreateSubscription(r.Context(), req.URL, req.Events, req.Secret)
+	if err != nil {
+		if err == service.ErrInvalidWebhookURL || err == service.ErrWebhookSecretEmpty || err == service.ErrUnknownWebhookEvent {
+			h.writeError(w, ErrorCodeNotFound, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"subscription": sub,
+	})
+}
+
+func (h *Handler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	subs, err := h.webhookService.GetSubscriptions(r.Context())
+	if err != nil {
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"subscriptions": subs,
+	})
+}
+
+func (h *Handler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req struct {
+		SubscriptionID int64 `json:"subscription_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err := h.webhookService.DeleteSubscription(r.Context(), req.SubscriptionID)
+	if err != nil {
+		if err == repository.ErrSubscriptionNotFound {
+			h.writeError(w, ErrorCodeNotFound, "webhook subscription not found", http.StatusNotFound)
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"subscription_id": req.SubscriptionID,
+	})
+}
+
+func (h *Handler) GetDeadDeliveries(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	deliveries, err := h.webhookService.GetDeadDeliveries(r.Context())
+	if err != nil {
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"deliveries": deliveries,
+	})
+}
+
+func (h *Handler) RetryDelivery(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req struct {
+		DeliveryID int64 `json:"delivery_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	delivery, err := h.webhookService.RetryDelivery(r.Context(), req.DeliveryID)
+	if err != nil {
+		if err == repository.ErrDeliveryNotFound {
+			h.writeError(w, ErrorCodeNotFound, "dead delivery not found", http.StatusNotFound)
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"delivery": delivery,
+	})
+}
+
+type UserMappingRequest struct {
+	Provider string `json:"provider"`
+	Login    string `json:"login"`
+	UserID   string `json:"user_id"`
+}
+
+func (h *Handler) MapIntegrationUser(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req UserMappingRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	mapping, err := h.integrationService.SetUserMapping(r.Context(), req.Provider, req.Login, req.UserID)
+	if err != nil {
+		if err == service.ErrUnknownProvider || err == service.ErrLoginRequired {
+			h.writeError(w, ErrorCodeNotFound, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err == repository.ErrUserNotFound {
+			h.writeError(w, ErrorCodeNotFound, "user not found", http.StatusNotFound)
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"mapping": mapping,
+	})
+}
+
+func (h *Handler) GetIntegrationUsers(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	provider := r.URL.Query().Get("provider")
+	mappings, err := h.integrationService.GetUserMappings(r.Context(), provider)
+	if err != nil {
+		if err == service.ErrUnknownProvider {
+			h.writeError(w, ErrorCodeNotFound, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"provider": provider,
+		"mappings": mappings,
+	})
+}
+
+func (h *Handler) UnmapIntegrationUser(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req UserMappingRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err := h.integrationService.DeleteUserMapping(r.Context(), req.Provider, req.Login)
+	if err != nil {
+		if err == service.ErrUnknownProvider {
+			h.writeError(w, ErrorCodeNotFound, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err == repository.ErrMappingNotFound {
+			h.writeError(w, ErrorCodeNotFound, "user mapping not found", http.StatusNotFound)
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"provider": req.Provider,
+		"login":    req.Login,
+	})
+}
+
+// maxWebhookBody limits incoming integration deliveries, GitHub caps them at 25 MB.
+const maxWebhookBody = 25 << 20
+
+func (h *Handler) GitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.integrationService.HandleGitHubWebhook(
+		r.Context(),
+		r.Header.Get(github.EventHeader),
+		r.Header.Get(github.SignatureHeader),
+		body,
+	)
+	if err != nil {
+		h.writeSyncError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"sync": result,
+	})
+}
+
+func (h *Handler) GitLabWebhook(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.integrationService.HandleGitLabWebhook(
+		r.Context(),
+		r.Header.Get(gitlab.EventHeader),
+		r.Header.Get(gitlab.TokenHeader),
+		body,
+	)
+	if err != nil {
+		h.writeSyncError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"sync": result,
+	})
+}
+
+func (h *Handler) GetReviewerSyncs(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		h.writeError(w, ErrorCodeNotFound, "pull_request_id is required", http.StatusBadRequest)
+		return
+	}
+
+	syncs, err := h.integrationService.GetReviewerSyncs(r.Context(), prID)
+	if err != nil {
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"pull_request_id": prID,
+		"reviewer_syncs":  syncs,
+	})
+}
+
+func (h *Handler) PushReviewerSync(w http.ResponseWriter, r *http.Request) {
+	if !h.checkMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req struct {
+		SyncID int64 `json:"sync_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, ErrorCodeNotFound, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	sync, err := h.integrationService.PushReviewerSync(r.Context(), req.SyncID)
+	if err != nil {
+		if err == service.ErrGitLabNotConfigured {
+			h.writeError(w, ErrorCodeNotConfigured, "gitlab client is not configured", http.StatusServiceUnavailable)
+			return
+		}
+		if err == repository.ErrSyncNotFound {
+			h.writeError(w, ErrorCodeNotFound, "reviewer sync not found", http.StatusNotFound)
+			return
+		}
+		h.writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"reviewer_sync": sync,
+	})
+}
+
+// writeSyncError maps errors of integration events onto the same responses
+// the PR endpoints return.
+func (h *Handler) writeSyncError(w http.ResponseWriter, err error) {
+	switch err {
+	case service.ErrInvalidSignature:
+		h.writeError(w, ErrorCodeInvalidSignature, "webhook signature does not match", http.StatusUnauthorized)
+	case service.ErrInvalidToken:
+		h.writeError(w, ErrorCodeInvalidSignature, "webhook token does not match", http.StatusUnauthorized)
+	case service.ErrInvalidPayload:
+		h.writeError(w, ErrorCodeNotFound, "invalid webhook payload", http.StatusBadRequest)
+	case service.ErrLoginNotMapped:
+		h.writeError(w, ErrorCodeNotFound, "author login is not mapped to a user", http.StatusNotFound)
+	case service.ErrAuthorNotFound, service.ErrTeamNotFound:
+		h.writeError(w, ErrorCodeNotFound, "author or team not found", http.StatusNotFound)
+	case repository.ErrPRNotFound:
+		h.writeError(w, ErrorCodeNotFound, "PR not found", http.StatusNotFound)
+	case service.ErrNotEnoughReviewers:
+		h.writeError(w, ErrorCodeNotEnoughReviewers, "not enough active reviewers to satisfy team policy", http.StatusConflict)
+	case service.ErrAllAtCapacity:
+		h.writeError(w, ErrorCodeAllAtCapacity, "all candidates are at review capacity", http.StatusConflict)
+	case service.ErrNotApproved:
+		h.writeError(w, ErrorCodeNotApproved, "PR does not have enough approvals", http.StatusConflict)
+	default:
+		if !h.writeStatusError(w, err) {
+			h.writeInternalError(w, err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+	"pr-reviewer-service/internal/repository/memory"
+	"pr-reviewer-service/internal/service"
+)
+
+// slowUserRepository stands in for a database that does not answer: stats
+// queries wait for their deadline and fail the way the postgres repositories
+// do, or fail with err right away when it is set.
+type slowUserRepository struct {
+	repository.UserRepository
+	deadline time.Duration
+	err      error
+}
+
+func (r *slowUserRepository) GetAllUsersStats(ctx context.Context, filter models.StatsFilter) ([]*models.UserStats, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	ctx, cancel := context.WithTimeout(ctx, r.deadline)
+	defer cancel()
+	<-ctx.Done()
+	return nil, repository.ErrTimeout
+}
+
+func TestInternalErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		userRepo   *slowUserRepository
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "query past its deadline",
+			userRepo:   &slowUserRepository{deadline: 20 * time.Millisecond},
+			wantStatus: http.StatusGatewayTimeout,
+			wantCode:   ErrorCodeTimeout,
+		},
+		{
+			name:       "other failure",
+			userRepo:   &slowUserRepository{err: errors.New("connection refused")},
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   ErrorCodeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := memory.NewStore()
+			stats := service.NewStatsService(tt.userRepo, memory.NewTeamRepository(store), memory.NewPullRequestRepository(store))
+			h := NewHandler(nil, nil, nil, stats, nil, nil, nil, nil, nil, nil, nil)
+
+			w := httptest.NewRecorder()
+			h.GetStatistics(w, httptest.NewRequest(http.MethodGet, "/stats", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			var response ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if response.Error.Code != tt.wantCode {
+				t.Errorf("code = %s, want %s", response.Error.Code, tt.wantCode)
+			}
+		})
+	}
+}
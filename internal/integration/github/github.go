@@ -0,0 +1,91 @@
+// Package github parses and verifies GitHub webhook deliveries.
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	EventHeader     = "X-GitHub-Event"
+	DeliveryHeader  = "X-GitHub-Delivery"
+	SignatureHeader = "X-Hub-Signature-256"
+
+	EventPing        = "ping"
+	EventPullRequest = "pull_request"
+
+	ActionOpened         = "opened"
+	ActionClosed         = "closed"
+	ActionReopened       = "reopened"
+	ActionReadyForReview = "ready_for_review"
+)
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// VerifySignature checks the X-Hub-Signature-256 header value, which is
+// "sha256=" followed by the hex encoded HMAC-SHA256 of the raw body.
+func VerifySignature(secret string, body []byte, signature string) error {
+	if secret == "" {
+		return ErrInvalidSignature
+	}
+	hexDigest, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return ErrInvalidSignature
+	}
+	digest, err := hex.DecodeString(hexDigest)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(digest, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+type Account struct {
+	Login string `json:"login"`
+}
+
+type Repository struct {
+	FullName string `json:"full_name"`
+}
+
+type PullRequest struct {
+	Number int     `json:"number"`
+	Title  string  `json:"title"`
+	Draft  bool    `json:"draft"`
+	Merged bool    `json:"merged"`
+	User   Account `json:"user"`
+}
+
+// PullRequestEvent holds the fields of a pull_request delivery the service uses.
+type PullRequestEvent struct {
+	Action      string      `json:"action"`
+	PullRequest PullRequest `json:"pull_request"`
+	Repository  Repository  `json:"repository"`
+	Sender      Account     `json:"sender"`
+}
+
+func ParsePullRequestEvent(body []byte) (*PullRequestEvent, error) {
+	var event PullRequestEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+	if event.Repository.FullName == "" || event.PullRequest.Number == 0 {
+		return nil, errors.New("pull_request event without repository or number")
+	}
+	return &event, nil
+}
+
+// PullRequestID builds the service PR id, e.g. "octo-org/api#42".
+func (e *PullRequestEvent) PullRequestID() string {
+	return fmt.Sprintf("%s#%d", e.Repository.FullName, e.PullRequest.Number)
+}
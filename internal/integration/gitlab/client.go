@@ -0,0 +1,104 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Client pushes reviewer assignments to merge requests.
+type Client interface {
+	AssignReviewers(ctx context.Context, projectID, mergeRequestIID int64, usernames []string) error
+}
+
+// HTTPClient sets reviewers with the /reassign_reviewer quick action in a
+// merge request note, which accepts usernames instead of numeric user ids and
+// replaces the current reviewers instead of adding to them.
+type HTTPClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+func NewHTTPClient(baseURL, token string, httpClient *http.Client) *HTTPClient {
+	return &HTTPClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		token:      token,
+		httpClient: httpClient,
+	}
+}
+
+func (c *HTTPClient) AssignReviewers(ctx context.Context, projectID, mergeRequestIID int64, usernames []string) error {
+	mentions := make([]string, len(usernames))
+	for i, username := range usernames {
+		mentions[i] = "@" + username
+	}
+	body, err := json.Marshal(map[string]string{
+		"body": "/reassign_reviewer " + strings.Join(mentions, " "),
+	})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/v4/projects/%d/merge_requests/%d/notes", c.baseURL, projectID, mergeRequestIID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("gitlab responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+type AssignCall struct {
+	ProjectID       int64
+	MergeRequestIID int64
+	Usernames       []string
+}
+
+// FakeClient records calls instead of talking to GitLab. Err, when set, is
+// returned from every call.
+type FakeClient struct {
+	mu    sync.Mutex
+	calls []AssignCall
+	Err   error
+}
+
+func NewFakeClient() *FakeClient {
+	return &FakeClient{}
+}
+
+func (c *FakeClient) AssignReviewers(ctx context.Context, projectID, mergeRequestIID int64, usernames []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls = append(c.calls, AssignCall{
+		ProjectID:       projectID,
+		MergeRequestIID: mergeRequestIID,
+		Usernames:       append([]string(nil), usernames...),
+	})
+	return c.Err
+}
+
+func (c *FakeClient) Calls() []AssignCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]AssignCall(nil), c.calls...)
+}
@@ -0,0 +1,81 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPClientAssignReviewers(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "accepted", status: http.StatusCreated},
+		{name: "rejected", status: http.StatusForbidden, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path, token, note string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				token = r.Header.Get("PRIVATE-TOKEN")
+				var body map[string]string
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("decode note: %v", err)
+				}
+				note = body["body"]
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			c := NewHTTPClient(server.URL+"/", "glpat-test", server.Client())
+			err := c.AssignReviewers(context.Background(), 42, 7, []string{"alice", "bob"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AssignReviewers error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if path != "/api/v4/projects/42/merge_requests/7/notes" {
+				t.Errorf("path = %q", path)
+			}
+			if token != "glpat-test" {
+				t.Errorf("PRIVATE-TOKEN = %q", token)
+			}
+			if note != "/reassign_reviewer @alice @bob" {
+				t.Errorf("note = %q, want the full reviewer list to replace the current one", note)
+			}
+		})
+	}
+}
+
+func TestHTTPClientAssignReviewersStopsWithContext(t *testing.T) {
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-block:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(block)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- NewHTTPClient(server.URL, "glpat-test", server.Client()).AssignReviewers(ctx, 1, 1, []string{"alice"})
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("AssignReviewers succeeded after its context was done")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("request was not cut off when its context was done")
+	}
+}
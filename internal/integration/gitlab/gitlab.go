@@ -0,0 +1,89 @@
+// Package gitlab parses GitLab merge request webhooks and pushes reviewer
+// assignments back to GitLab.
+package gitlab
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	TokenHeader = "X-Gitlab-Token"
+	EventHeader = "X-Gitlab-Event"
+
+	EventMergeRequest = "Merge Request Hook"
+
+	ActionOpen   = "open"
+	ActionReopen = "reopen"
+	ActionClose  = "close"
+	ActionMerge  = "merge"
+	ActionUpdate = "update"
+)
+
+var ErrInvalidToken = errors.New("invalid webhook token")
+
+// VerifyToken compares the X-Gitlab-Token header with the configured secret token.
+func VerifyToken(secret, token string) error {
+	if secret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(token)) != 1 {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+type User struct {
+	Username string `json:"username"`
+}
+
+type Project struct {
+	ID                int64  `json:"id"`
+	PathWithNamespace string `json:"path_with_namespace"`
+}
+
+type MergeRequest struct {
+	IID    int64  `json:"iid"`
+	Title  string `json:"title"`
+	Action string `json:"action"`
+	Draft  bool   `json:"draft"`
+}
+
+type BoolChange struct {
+	Previous bool `json:"previous"`
+	Current  bool `json:"current"`
+}
+
+type Changes struct {
+	Draft *BoolChange `json:"draft"`
+}
+
+// MergeRequestEvent holds the fields of a Merge Request Hook the service uses.
+// User is whoever triggered the event, for "open" it is the author.
+type MergeRequestEvent struct {
+	ObjectKind       string       `json:"object_kind"`
+	User             User         `json:"user"`
+	Project          Project      `json:"project"`
+	ObjectAttributes MergeRequest `json:"object_attributes"`
+	Changes          Changes      `json:"changes"`
+}
+
+func ParseMergeRequestEvent(body []byte) (*MergeRequestEvent, error) {
+	var event MergeRequestEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+	if event.ObjectKind != "merge_request" || event.Project.PathWithNamespace == "" || event.ObjectAttributes.IID == 0 {
+		return nil, errors.New("not a merge_request event")
+	}
+	return &event, nil
+}
+
+// PullRequestID builds the service PR id in GitLab notation, e.g. "group/api!42".
+func (e *MergeRequestEvent) PullRequestID() string {
+	return fmt.Sprintf("%s!%d", e.Project.PathWithNamespace, e.ObjectAttributes.IID)
+}
+
+// MarkedReady reports whether the update removed the draft flag.
+func (e *MergeRequestEvent) MarkedReady() bool {
+	return e.Changes.Draft != nil && e.Changes.Draft.Previous && !e.Changes.Draft.Current
+}
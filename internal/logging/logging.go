@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+type contextKey struct{}
+
+// New returns a logger writing JSON records of at least level to w. Records
+// logged with a context also carry the attributes attached to it by With.
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(&contextHandler{
+		Handler: slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}),
+	})
+}
+
+// ParseLevel parses a level name such as "debug", "info", "warn" or "error".
+// An empty value means info.
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if value == "" {
+		return slog.LevelInfo, nil
+	}
+	err := level.UnmarshalText([]byte(strings.TrimSpace(value)))
+	return level, err
+}
+
+// With returns a copy of ctx carrying the given key-value pairs or slog.Attr
+// values, which are then added to every record logged with the copy. A key
+// attached again replaces the earlier value.
+func With(ctx context.Context, args ...interface{}) context.Context {
+	record := slog.NewRecord(time.Time{}, 0, "", 0)
+	record.Add(args...)
+
+	var attrs []slog.Attr
+	if parent, ok := ctx.Value(contextKey{}).([]slog.Attr); ok {
+		attrs = append(attrs, parent...)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		for i := range attrs {
+			if attrs[i].Key == attr.Key {
+				attrs[i] = attr
+				return true
+			}
+		}
+		attrs = append(attrs, attr)
+		return true
+	})
+	return context.WithValue(ctx, contextKey{}, attrs)
+}
+
+// contextHandler adds the attributes attached by With to the records it
+// handles.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs, ok := ctx.Value(contextKey{}).([]slog.Attr); ok {
+		record = record.Clone()
+		record.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,100 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// decodeRecords parses the JSON records written to buf, one per line.
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("decode record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo)
+	ctx := With(context.Background(), "request_id", "req-1", "user_id", "bob")
+
+	logger.DebugContext(ctx, "hidden")
+	logger.InfoContext(ctx, "shown", "pull_request_id", "pr-1")
+	logger.With("component", "worker").WarnContext(ctx, "grouped")
+	logger.Info("no context")
+
+	records := decodeRecords(t, &buf)
+	if len(records) != 3 {
+		t.Fatalf("records = %v, want the three at info and above", records)
+	}
+	want := map[string]interface{}{"msg": "shown", "level": "INFO", "request_id": "req-1", "user_id": "bob", "pull_request_id": "pr-1"}
+	for key, value := range want {
+		if records[0][key] != value {
+			t.Errorf("first record %s = %v, want %v", key, records[0][key], value)
+		}
+	}
+	if records[1]["component"] != "worker" || records[1]["request_id"] != "req-1" {
+		t.Errorf("second record = %v, want the logger and context attributes", records[1])
+	}
+	if _, ok := records[2]["request_id"]; ok {
+		t.Errorf("record without context = %v, want no request_id", records[2])
+	}
+}
+
+func TestWith(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo)
+	parent := With(context.Background(), "request_id", "req-1", "user_id", "bob")
+	child := With(parent, "user_id", "carol", slog.Int("attempt", 2))
+
+	logger.InfoContext(parent, "parent")
+	logger.InfoContext(child, "child")
+
+	records := decodeRecords(t, &buf)
+	if records[0]["user_id"] != "bob" || records[0]["attempt"] != nil {
+		t.Errorf("parent record = %v, want it unchanged by the child", records[0])
+	}
+	if records[1]["user_id"] != "carol" || records[1]["request_id"] != "req-1" || records[1]["attempt"] != float64(2) {
+		t.Errorf("child record = %v, want user_id replaced and the rest kept", records[1])
+	}
+	if got := strings.Count(strings.Split(buf.String(), "\n")[1], `"user_id"`); got != 1 {
+		t.Errorf("child record has user_id %d times, want once", got)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    slog.Level
+		wantErr bool
+	}{
+		{value: "", want: slog.LevelInfo},
+		{value: "debug", want: slog.LevelDebug},
+		{value: " WARN ", want: slog.LevelWarn},
+		{value: "error", want: slog.LevelError},
+		{value: "verbose", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLevel(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// captureLogs sends the records of the default logger to the returned buffer
+// until the test ends.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(New(&buf, slog.LevelDebug))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{name: "from the client", incoming: "client-req-42", keep: true},
+		{name: "missing", incoming: ""},
+		{name: "with spaces", incoming: "two words"},
+		{name: "too long", incoming: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := captureLogs(t)
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				slog.InfoContext(r.Context(), "handled")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/team/get", nil)
+			if tt.incoming != "" {
+				req.Header.Set(RequestIDHeader, tt.incoming)
+			}
+			w := httptest.NewRecorder()
+			RequestID(next).ServeHTTP(w, req)
+
+			requestID := w.Header().Get(RequestIDHeader)
+			if tt.keep && requestID != tt.incoming {
+				t.Errorf("response request ID = %q, want %q", requestID, tt.incoming)
+			}
+			if !tt.keep && (requestID == tt.incoming || len(requestID) != 32) {
+				t.Errorf("response request ID = %q, want a generated one", requestID)
+			}
+			records := decodeRecords(t, buf)
+			if len(records) != 1 || records[0]["request_id"] != requestID {
+				t.Errorf("records = %v, want request_id %q", records, requestID)
+			}
+		})
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		wantLevel string
+	}{
+		{name: "success", status: http.StatusOK, wantLevel: "INFO"},
+		{name: "client error", status: http.StatusNotFound, wantLevel: "INFO"},
+		{name: "server error", status: http.StatusGatewayTimeout, wantLevel: "ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := captureLogs(t)
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte("body"))
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", nil)
+			req.Header.Set(RequestIDHeader, "req-1")
+			RequestID(AccessLog(next)).ServeHTTP(httptest.NewRecorder(), req)
+
+			records := decodeRecords(t, buf)
+			if len(records) != 1 {
+				t.Fatalf("records = %v, want one access log record", records)
+			}
+			want := map[string]interface{}{
+				"msg":        "request served",
+				"level":      tt.wantLevel,
+				"request_id": "req-1",
+				"method":     http.MethodPost,
+				"path":       "/pullRequest/merge",
+				"status":     float64(tt.status),
+				"bytes":      float64(4),
+			}
+			for key, value := range want {
+				if records[0][key] != value {
+					t.Errorf("%s = %v, want %v", key, records[0][key], value)
+				}
+			}
+		})
+	}
+}
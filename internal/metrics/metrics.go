@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const namespace = "pr_reviewer_"
+
+// durationBuckets are the upper bounds, in seconds, of the request latency
+// histogram.
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics are the metrics of the service, exposed by Handler.
+type Metrics struct {
+	registry *Registry
+
+	httpRequests              *CounterVec
+	httpDuration              *HistogramVec
+	assignments               *CounterVec
+	deactivationReassignments *CounterVec
+}
+
+func New() *Metrics {
+	registry := NewRegistry()
+	return &Metrics{
+		registry: registry,
+		httpRequests: registry.NewCounterVec(namespace+"http_requests_total",
+			"HTTP requests by route, method and status code.", "route", "method", "code"),
+		httpDuration: registry.NewHistogramVec(namespace+"http_request_duration_seconds",
+			"HTTP request latency by route and method.", durationBuckets, "route", "method"),
+		assignments: registry.NewCounterVec(namespace+"reviewer_assignments_total",
+			"Reviewer assignments by operation and outcome: full, partial, zero or no_candidate.", "operation", "outcome"),
+		deactivationReassignments: registry.NewCounterVec(namespace+"deactivation_reassignments_total",
+			"Attempts to hand over open reviews of deactivated users by result: success, failure or skipped.", "result"),
+	}
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return m.registry.Handler()
+}
+
+// Instrument counts requests served by mux and measures their latency per
+// registered pattern, so the number of series stays bounded. Requests that
+// match no pattern are counted under "other".
+func (m *Metrics) Instrument(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, route := mux.Handler(r)
+		if route == "" {
+			route = "other"
+		}
+
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		mux.ServeHTTP(recorder, r)
+
+		m.httpRequests.Inc(route, r.Method, strconv.Itoa(recorder.status))
+		m.httpDuration.Observe(time.Since(start).Seconds(), route, r.Method)
+	})
+}
+
+// AssignmentOutcome counts a reviewer assignment of the given operation.
+func (m *Metrics) AssignmentOutcome(operation, outcome string) {
+	m.assignments.Inc(operation, outcome)
+}
+
+// DeactivationReassignment counts an attempt to hand over an open review of a
+// deactivated user.
+func (m *Metrics) DeactivationReassignment(result string) {
+	m.deactivationReassignments.Inc(result)
+}
+
+// RegisterDB exposes the connection pool stats of db.
+func (m *Metrics) RegisterDB(db *sql.DB) {
+	gauge := func(name, help string, value func(s sql.DBStats) float64) {
+		m.registry.NewGaugeFunc(namespace+"db_"+name, help, func() float64 { return value(db.Stats()) })
+	}
+	counter := func(name, help string, value func(s sql.DBStats) float64) {
+		m.registry.NewCounterFunc(namespace+"db_"+name, help, func() float64 { return value(db.Stats()) })
+	}
+
+	gauge("max_open_connections", "Maximum number of open connections to the database.",
+		func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) })
+	gauge("open_connections", "Established connections, both in use and idle.",
+		func(s sql.DBStats) float64 { return float64(s.OpenConnections) })
+	gauge("in_use_connections", "Connections currently in use.",
+		func(s sql.DBStats) float64 { return float64(s.InUse) })
+	gauge("idle_connections", "Idle connections.",
+		func(s sql.DBStats) float64 { return float64(s.Idle) })
+	counter("wait_count_total", "Connections waited for.",
+		func(s sql.DBStats) float64 { return float64(s.WaitCount) })
+	counter("wait_duration_seconds_total", "Time blocked waiting for a new connection.",
+		func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() })
+	counter("max_idle_closed_total", "Connections closed due to SetMaxIdleConns.",
+		func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) })
+	counter("max_idle_time_closed_total", "Connections closed due to SetConnMaxIdleTime.",
+		func(s sql.DBStats) float64 { return float64(s.MaxIdleTimeClosed) })
+	counter("max_lifetime_closed_total", "Connections closed due to SetConnMaxLifetime.",
+		func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) })
+}
+
+// statusRecorder remembers the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// scrape fetches the metrics served by handler the way Prometheus does.
+func scrape(t *testing.T, handler http.Handler) string {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("scrape: %v", err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q, want the text exposition format", got)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("scrape: %v", err)
+	}
+	return string(body)
+}
+
+func assertLines(t *testing.T, body string, want ...string) {
+	t.Helper()
+	lines := make(map[string]bool)
+	for _, line := range strings.Split(body, "\n") {
+		lines[line] = true
+	}
+	for _, line := range want {
+		if !lines[line] {
+			t.Errorf("missing line %q in:\n%s", line, body)
+		}
+	}
+}
+
+func TestHandlerExposesServiceMetrics(t *testing.T) {
+	m := New()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/team/get", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	instrumented := m.Instrument(mux)
+	for _, path := range []string{"/team/get", "/unknown"} {
+		instrumented.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	m.AssignmentOutcome("create", "full")
+	m.DeactivationReassignment("success")
+
+	body := scrape(t, m.Handler())
+	assertLines(t, body,
+		"# HELP pr_reviewer_http_requests_total HTTP requests by route, method and status code.",
+		"# TYPE pr_reviewer_http_requests_total counter",
+		`pr_reviewer_http_requests_total{route="/team/get",method="GET",code="404"} 1`,
+		`pr_reviewer_http_requests_total{route="other",method="GET",code="404"} 1`,
+		"# TYPE pr_reviewer_http_request_duration_seconds histogram",
+		`pr_reviewer_http_request_duration_seconds_bucket{route="/team/get",method="GET",le="+Inf"} 1`,
+		`pr_reviewer_http_request_duration_seconds_count{route="/team/get",method="GET"} 1`,
+		"# TYPE pr_reviewer_reviewer_assignments_total counter",
+		`pr_reviewer_reviewer_assignments_total{operation="create",outcome="full"} 1`,
+		"# TYPE pr_reviewer_deactivation_reassignments_total counter",
+		`pr_reviewer_deactivation_reassignments_total{result="success"} 1`,
+	)
+
+	// Every family is introduced by HELP then TYPE before its first sample.
+	declared := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(body), "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case strings.HasPrefix(line, "# HELP "):
+			declared[fields[2]] = "HELP"
+		case strings.HasPrefix(line, "# TYPE "):
+			if declared[fields[2]] != "HELP" {
+				t.Errorf("TYPE of %s is not preceded by its HELP", fields[2])
+			}
+			declared[fields[2]] = fields[3]
+		default:
+			name := strings.FieldsFunc(line, func(r rune) bool { return r == '{' || r == ' ' })[0]
+			family := name
+			if declared[family] == "" {
+				for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+					family = strings.TrimSuffix(family, suffix)
+				}
+			}
+			if kind := declared[family]; kind == "" || kind == "HELP" {
+				t.Errorf("sample %q comes before the TYPE of %s", line, family)
+			}
+		}
+	}
+}
+
+func TestLabelAndHelpEscaping(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("escaped_total", "Help with a \\ backslash\nand a second line.", "value")
+	c.Inc(`say "hi"`)
+	c.Inc(`C:\path`)
+	c.Inc("two\nlines")
+
+	assertLines(t, scrape(t, r.Handler()),
+		`# HELP escaped_total Help with a \\ backslash\nand a second line.`,
+		`escaped_total{value="say \"hi\""} 1`,
+		`escaped_total{value="C:\\path"} 1`,
+		`escaped_total{value="two\nlines"} 1`,
+	)
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogramVec("latency_seconds", "Latency.", []float64{0.1, 1, 2.5}, "route")
+	for _, value := range []float64{0.05, 0.1, 0.5, 3} {
+		h.Observe(value, "/a")
+	}
+	h.Observe(0.5, "/b")
+
+	assertLines(t, scrape(t, r.Handler()),
+		"# TYPE latency_seconds histogram",
+		// Buckets are cumulative and a value equal to a bound falls into it.
+		`latency_seconds_bucket{route="/a",le="0.1"} 2`,
+		`latency_seconds_bucket{route="/a",le="1"} 3`,
+		`latency_seconds_bucket{route="/a",le="2.5"} 3`,
+		`latency_seconds_bucket{route="/a",le="+Inf"} 4`,
+		`latency_seconds_sum{route="/a"} 3.65`,
+		`latency_seconds_count{route="/a"} 4`,
+		`latency_seconds_bucket{route="/b",le="0.1"} 0`,
+		`latency_seconds_bucket{route="/b",le="+Inf"} 1`,
+		`latency_seconds_count{route="/b"} 1`,
+	)
+}
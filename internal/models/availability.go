@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Availability is a window during which the user must not get new reviews,
+// e.g. a vacation or a sick leave.
+type Availability struct {
+	AvailabilityID      int64      `db:"availability_id" json:"availability_id"`
+	UserID              string     `db:"user_id" json:"user_id"`
+	StartsAt            time.Time  `db:"starts_at" json:"starts_at"`
+	EndsAt              time.Time  `db:"ends_at" json:"ends_at"`
+	Reason              string     `db:"reason" json:"reason"`
+	ReassignReviews     bool       `db:"reassign_reviews" json:"reassign_reviews"`
+	ReviewsReassignedAt *time.Time `db:"reviews_reassigned_at" json:"reviews_reassigned_at,omitempty"`
+}
+
+func (a *Availability) Covers(t time.Time) bool {
+	return !t.Before(a.StartsAt) && t.Before(a.EndsAt)
+}
+
+type AvailabilityResponse struct {
+	Availability        *Availability `json:"availability"`
+	ReassignedPRs       []string      `json:"reassigned_prs,omitempty"`
+	FailedReassignments []string      `json:"failed_reassignments,omitempty"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+type EventType string
+
+const (
+	EventPRCreated        EventType = "pr_created"
+	EventReviewerAssigned EventType = "reviewer_assigned"
+	EventReviewerReplaced EventType = "reviewer_replaced"
+	EventReviewSubmitted  EventType = "review_submitted"
+	EventMarkedReady      EventType = "marked_ready"
+	EventMerged           EventType = "merged"
+	EventClosed           EventType = "closed"
+	EventReopened         EventType = "reopened"
+	EventUserDeactivated  EventType = "user_deactivated"
+	EventUserReactivated  EventType = "user_reactivated"
+)
+
+// EventDetailsFallback marks reviewer_assigned and reviewer_replaced events for
+// reviewers taken from a fallback team.
+const EventDetailsFallback = "fallback"
+
+// EventDetailsReactivation marks reviewer_replaced events for reviews handed
+// over to a reactivated user.
+const EventDetailsReactivation = "reactivation"
+
+// Event is an append-only audit record. UserID is the user the event is about
+// (author, assigned or new reviewer, deactivated or reactivated user); PreviousUserID is set
+// for reviewer_replaced only.
+type Event struct {
+	EventID        int64     `json:"event_id"`
+	EventType      EventType `json:"event_type"`
+	PullRequestID  string    `json:"pull_request_id,omitempty"`
+	UserID         string    `json:"user_id,omitempty"`
+	PreviousUserID string    `json:"previous_user_id,omitempty"`
+	Actor          string    `json:"actor"`
+	Strategy       string    `json:"strategy,omitempty"`
+	Details        string    `json:"details,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,58 @@
+package models
+
+// Exclusion reasons reported in a selection explanation.
+const (
+	ExclusionAuthor          = "author"
+	ExclusionReplaced        = "replaced"
+	ExclusionInactive        = "inactive"
+	ExclusionOnVacation      = "on_vacation"
+	ExclusionAlreadyAssigned = "already_assigned"
+	ExclusionAtCapacity      = "at_capacity"
+	// ExclusionOther is reported for a member left out for none of the
+	// reasons above.
+	ExclusionOther = "other"
+)
+
+// Candidate pool sources in the order they are consulted.
+const (
+	PoolCodeOwners = "codeowners"
+	PoolTeam       = "team"
+	PoolFallback   = "fallback"
+)
+
+const (
+	OperationCreate   = "create"
+	OperationReassign = "reassign"
+)
+
+// Explanation describes how reviewers were (or would be) selected for a PR.
+type Explanation struct {
+	Operation      string           `json:"operation"`
+	PullRequestID  string           `json:"pull_request_id,omitempty"`
+	AuthorID       string           `json:"author_id"`
+	TeamName       string           `json:"team_name"`
+	ReplacedUserID string           `json:"replaced_user_id,omitempty"`
+	ReviewerCount  int              `json:"reviewer_count"`
+	Pools          []*CandidatePool `json:"pools"`
+	Picked         []string         `json:"picked"`
+	// Error is set when the selection failed, e.g. with NO_CANDIDATE.
+	Error string `json:"error,omitempty"`
+}
+
+// CandidatePool is one round of selection: who was considered, who was left
+// out and why, the scores the strategy ranked by and who it picked.
+type CandidatePool struct {
+	Source     string             `json:"source"`
+	TeamName   string             `json:"team_name,omitempty"`
+	Strategy   string             `json:"strategy,omitempty"`
+	Slots      int                `json:"slots"`
+	Candidates []string           `json:"candidates"`
+	Exclusions []Exclusion        `json:"exclusions"`
+	Scores     map[string]float64 `json:"scores,omitempty"`
+	Picked     []string           `json:"picked"`
+}
+
+type Exclusion struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+type CheckStatus string
+
+const (
+	CheckOK     CheckStatus = "ok"
+	CheckFailed CheckStatus = "failed"
+)
+
+type WorkerState string
+
+const (
+	// WorkerIdle means the worker waits for its next pass.
+	WorkerIdle    WorkerState = "idle"
+	WorkerRunning WorkerState = "running"
+	// WorkerStuck means the current pass has been running for longer than
+	// the configured threshold.
+	WorkerStuck WorkerState = "stuck"
+	// WorkerStopped means the worker loop is not running, e.g. during
+	// shutdown.
+	WorkerStopped WorkerState = "stopped"
+)
+
+// Readiness is the result of the readiness checks. Status is ok only when
+// every check is.
+type Readiness struct {
+	Status CheckStatus     `json:"status"`
+	Checks ReadinessChecks `json:"checks"`
+}
+
+type ReadinessChecks struct {
+	Database   *DatabaseCheck   `json:"database"`
+	Migrations *MigrationsCheck `json:"migrations"`
+	Workers    *WorkersCheck    `json:"workers"`
+}
+
+type DatabaseCheck struct {
+	Status     CheckStatus `json:"status"`
+	DurationMs int64       `json:"duration_ms"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// MigrationsCheck compares the applied migration version with the one the
+// service is built for; a newer version passes. A dirty version means the last
+// migration failed halfway.
+type MigrationsCheck struct {
+	Status          CheckStatus `json:"status"`
+	Version         int64       `json:"version"`
+	ExpectedVersion int64       `json:"expected_version"`
+	Dirty           bool        `json:"dirty"`
+	Error           string      `json:"error,omitempty"`
+}
+
+type WorkersCheck struct {
+	Status  CheckStatus     `json:"status"`
+	Workers []*WorkerStatus `json:"workers"`
+}
+
+type WorkerStatus struct {
+	Name              string      `json:"name"`
+	State             WorkerState `json:"state"`
+	LastStartedAt     *time.Time  `json:"last_started_at,omitempty"`
+	LastFinishedAt    *time.Time  `json:"last_finished_at,omitempty"`
+	RunningForSeconds int64       `json:"running_for_seconds,omitempty"`
+}
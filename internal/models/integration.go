@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// UserMapping links an account of an external code hosting to a user of the service.
+type UserMapping struct {
+	Provider string `json:"provider"`
+	Login    string `json:"login"`
+	UserID   string `json:"user_id"`
+}
+
+const (
+	SyncCreated  = "created"
+	SyncMerged   = "merged"
+	SyncClosed   = "closed"
+	SyncReopened = "reopened"
+	SyncReady    = "ready"
+	SyncIgnored  = "ignored"
+)
+
+// SyncResult describes what an incoming integration event did to the PR.
+type SyncResult struct {
+	Provider      string        `json:"provider"`
+	Event         string        `json:"event"`
+	Action        string        `json:"action"`
+	PullRequestID string        `json:"pull_request_id,omitempty"`
+	Result        string        `json:"result"`
+	PR            *PullRequest  `json:"pr,omitempty"`
+	ReviewerSync  *ReviewerSync `json:"reviewer_sync,omitempty"`
+}
+
+type SyncStatus string
+
+const (
+	SyncPending SyncStatus = "PENDING"
+	SyncPushed  SyncStatus = "PUSHED"
+	SyncFailed  SyncStatus = "FAILED"
+)
+
+// ReviewerSync records reviewers assigned to a merge request of an external
+// code hosting and whether they were pushed back to it.
+type ReviewerSync struct {
+	SyncID          int64      `json:"sync_id"`
+	Provider        string     `json:"provider"`
+	PullRequestID   string     `json:"pull_request_id"`
+	ProjectID       int64      `json:"project_id"`
+	MergeRequestIID int64      `json:"merge_request_iid"`
+	Reviewers       []string   `json:"reviewers"`
+	Status          SyncStatus `json:"status"`
+	Error           string     `json:"error,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	PushedAt        *time.Time `json:"pushed_at,omitempty"`
+}
@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+const JobTypeDeactivation = "deactivation"
+
+type JobStatus string
+
+const (
+	JobRunning   JobStatus = "RUNNING"
+	JobCompleted JobStatus = "COMPLETED"
+)
+
+type JobItemStatus string
+
+const (
+	JobItemPending JobItemStatus = "PENDING"
+	JobItemDone    JobItemStatus = "DONE"
+	// JobItemSkipped means the work became unnecessary, e.g. the PR was merged
+	// or the reviewer was already replaced by someone else.
+	JobItemSkipped JobItemStatus = "SKIPPED"
+	JobItemFailed  JobItemStatus = "FAILED"
+)
+
+// Job is background work started by an API call. A deactivation job has an
+// item per open PR of a deactivated reviewer; the job is COMPLETED once none
+// of its items is PENDING.
+type Job struct {
+	JobID      int64       `json:"job_id"`
+	JobType    string      `json:"job_type"`
+	TeamName   string      `json:"team_name"`
+	UserIDs    []string    `json:"user_ids"`
+	Actor      string      `json:"actor"`
+	Status     JobStatus   `json:"status"`
+	Progress   JobProgress `json:"progress"`
+	Items      []*JobItem  `json:"items"`
+	CreatedAt  time.Time   `json:"created_at"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty"`
+}
+
+type JobProgress struct {
+	Total   int `json:"total"`
+	Pending int `json:"pending"`
+	Done    int `json:"done"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+// JobItem replaces reviewer UserID on PullRequestID.
+type JobItem struct {
+	ItemID        int64         `json:"item_id"`
+	JobID         int64         `json:"-"`
+	PullRequestID string        `json:"pull_request_id"`
+	UserID        string        `json:"user_id"`
+	Status        JobItemStatus `json:"status"`
+	Attempts      int           `json:"attempts"`
+	NextAttemptAt time.Time     `json:"next_attempt_at"`
+	LastError     string        `json:"last_error,omitempty"`
+	ReplacedBy    string        `json:"replaced_by,omitempty"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	// Actor started the job; reassignments are recorded on behalf of them.
+	Actor string `json:"-"`
+}
+
+// Count fills Progress from the statuses of Items.
+func (j *Job) Count() {
+	j.Progress = JobProgress{Total: len(j.Items)}
+	for _, item := range j.Items {
+		switch item.Status {
+		case JobItemPending:
+			j.Progress.Pending++
+		case JobItemDone:
+			j.Progress.Done++
+		case JobItemSkipped:
+			j.Progress.Skipped++
+		case JobItemFailed:
+			j.Progress.Failed++
+		}
+	}
+}
@@ -3,10 +3,15 @@ package models
 import "time"
 
 type User struct {
-	UserID   string `db:"user_id" json:"user_id"`
-	Username string `db:"username" json:"username"`
-	TeamName string `db:"team_name" json:"team_name"`
-	IsActive bool   `db:"is_active" json:"is_active"`
+	UserID         string `db:"user_id" json:"user_id"`
+	Username       string `db:"username" json:"username"`
+	TeamName       string `db:"team_name" json:"team_name"`
+	IsActive       bool   `db:"is_active" json:"is_active"`
+	MaxOpenReviews *int   `db:"max_open_reviews" json:"max_open_reviews,omitempty"`
+}
+
+func (u *User) HasCapacity(openReviews int) bool {
+	return u.MaxOpenReviews == nil || openReviews < *u.MaxOpenReviews
 }
 
 type Team struct {
@@ -15,26 +20,90 @@ type Team struct {
 }
 
 type TeamMember struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID         string `json:"user_id"`
+	Username       string `json:"username"`
+	IsActive       bool   `json:"is_active"`
+	MaxOpenReviews *int   `json:"max_open_reviews,omitempty"`
 }
 
 type PullRequestStatus string
 
 const (
+	StatusDraft  PullRequestStatus = "DRAFT"
 	StatusOpen   PullRequestStatus = "OPEN"
+	StatusClosed PullRequestStatus = "CLOSED"
 	StatusMerged PullRequestStatus = "MERGED"
 )
 
+// prTransitions lists the statuses a PR may move to from its current status.
+var prTransitions = map[PullRequestStatus][]PullRequestStatus{
+	StatusDraft:  {StatusOpen, StatusClosed},
+	StatusOpen:   {StatusMerged, StatusClosed},
+	StatusClosed: {StatusOpen},
+	StatusMerged: {},
+}
+
+func (s PullRequestStatus) CanTransitionTo(next PullRequestStatus) bool {
+	for _, allowed := range prTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 type PullRequest struct {
 	PullRequestID     string            `db:"pull_request_id" json:"pull_request_id"`
 	PullRequestName   string            `db:"pull_request_name" json:"pull_request_name"`
 	AuthorID          string            `db:"author_id" json:"author_id"`
 	Status            PullRequestStatus `db:"status" json:"status"`
 	AssignedReviewers []string          `json:"assigned_reviewers"`
+	FallbackReviewers []string          `json:"fallback_reviewers,omitempty"`
 	CreatedAt         *time.Time        `db:"created_at" json:"createdAt,omitempty"`
 	MergedAt          *time.Time        `db:"merged_at" json:"mergedAt,omitempty"`
+	ClosedAt          *time.Time        `db:"closed_at" json:"closedAt,omitempty"`
+	ChangedFiles      []string          `db:"changed_files" json:"changed_files,omitempty"`
+	Reviews           []Review          `json:"reviews,omitempty"`
+}
+
+type ReviewVerdict string
+
+const (
+	VerdictApproved         ReviewVerdict = "APPROVED"
+	VerdictChangesRequested ReviewVerdict = "CHANGES_REQUESTED"
+	VerdictCommented        ReviewVerdict = "COMMENTED"
+)
+
+func (v ReviewVerdict) IsValid() bool {
+	return v == VerdictApproved || v == VerdictChangesRequested || v == VerdictCommented
+}
+
+// Review is the latest verdict submitted by an assigned reviewer.
+type Review struct {
+	UserID      string        `db:"user_id" json:"user_id"`
+	Verdict     ReviewVerdict `db:"verdict" json:"verdict"`
+	SubmittedAt time.Time     `db:"submitted_at" json:"submitted_at"`
+}
+
+func (pr *PullRequest) ApprovalCount() int {
+	count := 0
+	for _, review := range pr.Reviews {
+		if review.Verdict == VerdictApproved {
+			count++
+		}
+	}
+	return count
+}
+
+// HasChangesRequested reports whether an assigned reviewer's latest verdict
+// is CHANGES_REQUESTED.
+func (pr *PullRequest) HasChangesRequested() bool {
+	for _, review := range pr.Reviews {
+		if review.Verdict == VerdictChangesRequested {
+			return true
+		}
+	}
+	return false
 }
 
 type PullRequestShort struct {
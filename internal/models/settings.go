@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+const (
+	DefaultReviewerCount = 2
+	MaxReviewerCount     = 10
+)
+
+const (
+	CapacityPolicyAssignFewer = "assign_fewer"
+	CapacityPolicyFail        = "fail"
+)
+
+type TeamSettings struct {
+	TeamName          string `db:"team_name" json:"team_name"`
+	ReviewerCount     int    `db:"reviewer_count" json:"reviewer_count"`
+	MinReviewers      int    `db:"min_reviewers" json:"min_reviewers"`
+	AllowSelfReview   bool   `db:"allow_self_review" json:"allow_self_review"`
+	CapacityPolicy    string `db:"capacity_policy" json:"capacity_policy"`
+	RequiredApprovals int    `db:"required_approvals" json:"required_approvals"`
+	// FallbackTeams are asked in order when the team cannot fill all reviewer slots.
+	FallbackTeams []string `db:"fallback_teams" json:"fallback_teams"`
+}
+
+func DefaultTeamSettings(teamName string) *TeamSettings {
+	return &TeamSettings{
+		TeamName:       teamName,
+		ReviewerCount:  DefaultReviewerCount,
+		CapacityPolicy: CapacityPolicyAssignFewer,
+		FallbackTeams:  []string{},
+	}
+}
+
+// TeamCodeOwners keeps the CODEOWNERS file uploaded for a team. Content is
+// empty and UpdatedAt is nil when nothing was uploaded.
+type TeamCodeOwners struct {
+	TeamName  string     `db:"team_name" json:"team_name"`
+	Content   string     `db:"content" json:"content"`
+	UpdatedAt *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+}
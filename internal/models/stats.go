@@ -1,21 +1,123 @@
 package models
 
+import "time"
+
+// StatsFilter limits statistics to the members of TeamName, all users when it
+// is empty, and to activity within [From, To).
+type StatsFilter struct {
+	TeamName string
+	From     time.Time
+	To       time.Time
+}
+
+// UserStats counts PRs created, reviews submitted and PRs merged within the
+// period. OpenReviewCount is the current load and does not depend on it.
 type UserStats struct {
-	UserID                  string `json:"user_id"`
-	Username                string `json:"username"`
-	AssignedAsReviewerCount int    `json:"assigned_as_reviewer_count"`
-	AuthoredPRCount         int    `json:"authored_pr_count"`
+	UserID                  string  `json:"user_id"`
+	Username                string  `json:"username"`
+	TeamName                string  `json:"team_name"`
+	IsActive                bool    `json:"is_active"`
+	AssignedAsReviewerCount int     `json:"assigned_as_reviewer_count"`
+	AuthoredPRCount         int     `json:"authored_pr_count"`
+	OpenReviewCount         int     `json:"open_review_count"`
+	MaxOpenReviews          *int    `json:"max_open_reviews,omitempty"`
+	ReviewCount             int     `json:"review_count"`
+	ReviewsPerWeek          float64 `json:"reviews_per_week"`
+	// MedianTimeToMergeSeconds is taken over the merged PRs the user reviews.
+	MedianTimeToMergeSeconds *int64 `json:"median_time_to_merge_seconds,omitempty"`
+	// FirstReviewAt is the earliest review within the period; it sets the
+	// period start for reviews per week when no from is given.
+	FirstReviewAt *time.Time `json:"-"`
+}
+
+// TeamStats sums the stats of the team members. Its median time to merge is
+// taken over the merged PRs authored by the members.
+type TeamStats struct {
+	TeamName                 string  `json:"team_name"`
+	MemberCount              int     `json:"member_count"`
+	ActiveMemberCount        int     `json:"active_member_count"`
+	AssignedAsReviewerCount  int     `json:"assigned_as_reviewer_count"`
+	AuthoredPRCount          int     `json:"authored_pr_count"`
+	OpenReviewCount          int     `json:"open_review_count"`
+	AvgOpenReviews           float64 `json:"avg_open_reviews"`
+	ReviewCount              int     `json:"review_count"`
+	ReviewsPerWeek           float64 `json:"reviews_per_week"`
+	MergedPRCount            int     `json:"merged_pr_count"`
+	MedianTimeToMergeSeconds *int64  `json:"median_time_to_merge_seconds,omitempty"`
+}
+
+// StatsResponse covers the period from From, omitted when neither the filter
+// nor any review sets it, to To.
+type StatsResponse struct {
+	From       *time.Time   `json:"from,omitempty"`
+	To         time.Time    `json:"to"`
+	Teams      []*TeamStats `json:"teams"`
+	Statistics []*UserStats `json:"statistics"`
 }
 
 type DeactivationRequest struct {
 	TeamName string   `json:"team_name"`
 	UserIDs  []string `json:"user_ids"`
+	// Atomic deactivates the users and reassigns all their open reviews in one
+	// transaction instead of a background job.
+	Atomic bool `json:"atomic"`
 }
 
+// DeactivationResponse is returned before open reviews are handed over; Job
+// tracks the reassignments and is nil when nobody was deactivated.
+// ReassignedPRs and FailedReassignments list the PRs whose job items were
+// already DONE or FAILED when the response was made; they are complete in
+// atomic mode and in dry runs, where the job is processed in the request.
 type DeactivationResponse struct {
 	TeamName            string   `json:"team_name"`
-	DeactivatedUsers   []string `json:"deactivated_users"`
+	DeactivatedUsers    []string `json:"deactivated_users"`
 	ReassignedPRs       []string `json:"reassigned_prs"`
 	FailedReassignments []string `json:"failed_reassignments,omitempty"`
+	Job                 *Job     `json:"job,omitempty"`
+	// Reassignments replaces Job when an atomic deactivation is rolled back,
+	// since the job was never saved.
+	Reassignments []*Reassignment `json:"reassignments,omitempty"`
+	DryRun        bool            `json:"dry_run,omitempty"`
+}
+
+// Reassignment is the outcome of replacing UserID on PullRequestID.
+type Reassignment struct {
+	PullRequestID string        `json:"pull_request_id"`
+	UserID        string        `json:"user_id"`
+	Status        JobItemStatus `json:"status"`
+	ReplacedBy    string        `json:"replaced_by,omitempty"`
+	Error         string        `json:"error,omitempty"`
+}
+
+type ReactivationRequest struct {
+	UserID string `json:"user_id"`
+	// RestoreReviews hands back open reviews the user was replaced on since
+	// their last deactivation, if the replacement still holds them.
+	RestoreReviews bool `json:"restore_reviews"`
+	// Limit is how many open reviews to take over from the most loaded
+	// teammates; 0 only reactivates the user.
+	Limit int `json:"limit"`
+}
+
+type ReactivationReason string
+
+const (
+	ReactivationRestored   ReactivationReason = "RESTORED"
+	ReactivationRebalanced ReactivationReason = "REBALANCED"
+)
+
+// MovedReview is an open review handed over to the reactivated user.
+type MovedReview struct {
+	PullRequestID string             `json:"pull_request_id"`
+	FromUserID    string             `json:"from_user_id"`
+	Reason        ReactivationReason `json:"reason"`
 }
 
+// ReactivationResponse lists the reviews moved to the user together with the
+// open review load of the user and active teammates before and after.
+type ReactivationResponse struct {
+	User         *User          `json:"user"`
+	MovedReviews []*MovedReview `json:"moved_reviews"`
+	LoadsBefore  map[string]int `json:"loads_before"`
+	LoadsAfter   map[string]int `json:"loads_after"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	WebhookPRCreated        = "pr.created"
+	WebhookPRReassigned     = "pr.reassigned"
+	WebhookPRMerged         = "pr.merged"
+	WebhookUsersDeactivated = "users.deactivated"
+)
+
+var WebhookEvents = []string{
+	WebhookPRCreated,
+	WebhookPRReassigned,
+	WebhookPRMerged,
+	WebhookUsersDeactivated,
+}
+
+// PRReassigned is the data of pr.reassigned notifications.
+type PRReassigned struct {
+	PR         *PullRequest `json:"pr"`
+	OldUserID  string       `json:"old_user_id"`
+	ReplacedBy string       `json:"replaced_by"`
+}
+
+func IsWebhookEvent(eventType string) bool {
+	for _, known := range WebhookEvents {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookSubscription receives the listed events, or all events when the list
+// is empty. The secret is only used to sign deliveries and is never returned.
+type WebhookSubscription struct {
+	SubscriptionID int64     `json:"subscription_id"`
+	URL            string    `json:"url"`
+	Events         []string  `json:"events"`
+	Secret         string    `json:"-"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func (s *WebhookSubscription) Accepts(eventType string) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, event := range s.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "PENDING"
+	DeliveryDelivered DeliveryStatus = "DELIVERED"
+	DeliveryDead      DeliveryStatus = "DEAD"
+)
+
+type WebhookDelivery struct {
+	DeliveryID     int64           `json:"delivery_id"`
+	SubscriptionID int64           `json:"subscription_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+type AvailabilityRepository struct {
+	store *Store
+}
+
+func NewAvailabilityRepository(store *Store) *AvailabilityRepository {
+	return &AvailabilityRepository{store: store}
+}
+
+func (r *AvailabilityRepository) Create(ctx context.Context, a *models.Availability) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[a.UserID]; !ok {
+		return repository.ErrUserNotFound
+	}
+
+	a.AvailabilityID = r.store.nextID()
+	r.store.availability[a.AvailabilityID] = copyAvailability(a)
+	return nil
+}
+
+func (r *AvailabilityRepository) GetByUser(ctx context.Context, userID string) ([]*models.Availability, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.filterAvailability(func(a *models.Availability) bool {
+		return a.UserID == userID
+	}), nil
+}
+
+func (r *AvailabilityRepository) Delete(ctx context.Context, availabilityID int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.availability[availabilityID]; !ok {
+		return repository.ErrAvailabilityNotFound
+	}
+	delete(r.store.availability, availabilityID)
+	return nil
+}
+
+func (r *AvailabilityRepository) GetStartedPendingReassignment(ctx context.Context) ([]*models.Availability, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	now := r.store.now()
+	return r.store.filterAvailability(func(a *models.Availability) bool {
+		return a.ReassignReviews && a.ReviewsReassignedAt == nil && a.Covers(now)
+	}), nil
+}
+
+func (r *AvailabilityRepository) MarkReviewsReassigned(ctx context.Context, availabilityID int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if a, ok := r.store.availability[availabilityID]; ok {
+		now := r.store.now()
+		a.ReviewsReassignedAt = &now
+	}
+	return nil
+}
+
+func (s *Store) isAway(userID string, at time.Time) bool {
+	for _, a := range s.availability {
+		if a.UserID == userID && a.Covers(at) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) filterAvailability(match func(a *models.Availability) bool) []*models.Availability {
+	var windows []*models.Availability
+	for _, a := range s.availability {
+		if match(a) {
+			windows = append(windows, copyAvailability(a))
+		}
+	}
+	sort.Slice(windows, func(i, j int) bool {
+		if !windows[i].StartsAt.Equal(windows[j].StartsAt) {
+			return windows[i].StartsAt.Before(windows[j].StartsAt)
+		}
+		return windows[i].AvailabilityID < windows[j].AvailabilityID
+	})
+	return windows
+}
+
+func copyAvailability(a *models.Availability) *models.Availability {
+	c := *a
+	if a.ReviewsReassignedAt != nil {
+		reassignedAt := *a.ReviewsReassignedAt
+		c.ReviewsReassignedAt = &reassignedAt
+	}
+	return &c
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+	"pr-reviewer-service/internal/models"
+)
+
+type EventRepository struct {
+	store *Store
+}
+
+func NewEventRepository(store *Store) *EventRepository {
+	return &EventRepository{store: store}
+}
+
+func (r *EventRepository) Append(ctx context.Context, event *models.Event) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	event.EventID = r.store.nextID()
+	event.CreatedAt = r.store.now()
+	c := *event
+	r.store.events = append(r.store.events, &c)
+	return nil
+}
+
+func (r *EventRepository) GetByPullRequest(ctx context.Context, prID string) ([]*models.Event, error) {
+	return r.filter(func(event *models.Event) bool {
+		return event.PullRequestID == prID
+	}), nil
+}
+
+func (r *EventRepository) GetByUser(ctx context.Context, userID string) ([]*models.Event, error) {
+	return r.filter(func(event *models.Event) bool {
+		return event.UserID == userID || event.PreviousUserID == userID || event.Actor == userID
+	}), nil
+}
+
+func (r *EventRepository) filter(match func(event *models.Event) bool) []*models.Event {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	events := make([]*models.Event, 0)
+	for _, event := range r.store.events {
+		if match(event) {
+			c := *event
+			events = append(events, &c)
+		}
+	}
+	return events
+}
@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+type JobRepository struct {
+	store *Store
+}
+
+func NewJobRepository(store *Store) *JobRepository {
+	return &JobRepository{store: store}
+}
+
+func (r *JobRepository) CreateJob(ctx context.Context, job *models.Job) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := r.store.now()
+	job.JobID = r.store.nextID()
+	job.Status = models.JobRunning
+	job.CreatedAt = now
+	job.FinishedAt = nil
+	if len(job.Items) == 0 {
+		job.Status = models.JobCompleted
+		job.FinishedAt = &now
+	}
+
+	for _, item := range job.Items {
+		item.ItemID = r.store.nextID()
+		item.JobID = job.JobID
+		item.Status = models.JobItemPending
+		item.Attempts = 0
+		item.NextAttemptAt = now
+		item.LastError = ""
+		item.ReplacedBy = ""
+		item.UpdatedAt = now
+		item.Actor = job.Actor
+		r.store.jobItems[item.ItemID] = copyJobItem(item)
+	}
+	job.Count()
+
+	stored := copyJob(job)
+	stored.Items = nil
+	r.store.jobs[job.JobID] = stored
+	return nil
+}
+
+func (r *JobRepository) GetJob(ctx context.Context, jobID int64) (*models.Job, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	job, ok := r.store.jobs[jobID]
+	if !ok {
+		return nil, repository.ErrJobNotFound
+	}
+
+	c := copyJob(job)
+	c.Items = r.store.filterJobItems(func(item *models.JobItem) bool {
+		return item.JobID == jobID
+	})
+	c.Count()
+	return c, nil
+}
+
+func (r *JobRepository) ClaimDueItems(ctx context.Context, limit int, lease time.Duration) ([]*models.JobItem, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := r.store.now()
+	due := r.store.filterJobItems(func(item *models.JobItem) bool {
+		return item.Status == models.JobItemPending && !item.NextAttemptAt.After(now)
+	})
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	for _, item := range due {
+		item.NextAttemptAt = now.Add(lease)
+		r.store.jobItems[item.ItemID].NextAttemptAt = item.NextAttemptAt
+	}
+	return due, nil
+}
+
+func (r *JobRepository) SaveItemAttempt(ctx context.Context, item *models.JobItem) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.jobItems[item.ItemID]; !ok {
+		return repository.ErrJobNotFound
+	}
+	item.UpdatedAt = r.store.now()
+	r.store.jobItems[item.ItemID] = copyJobItem(item)
+	return nil
+}
+
+func (r *JobRepository) FinishJob(ctx context.Context, jobID int64) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, ok := r.store.jobs[jobID]
+	if !ok || job.Status != models.JobRunning {
+		return false, nil
+	}
+	for _, item := range r.store.jobItems {
+		if item.JobID == jobID && item.Status == models.JobItemPending {
+			return false, nil
+		}
+	}
+
+	now := r.store.now()
+	job.Status = models.JobCompleted
+	job.FinishedAt = &now
+	return true, nil
+}
+
+func (r *JobRepository) RequeueFailedItems(ctx context.Context, jobID int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, ok := r.store.jobs[jobID]
+	if !ok {
+		return repository.ErrJobNotFound
+	}
+
+	now := r.store.now()
+	requeued := 0
+	for _, item := range r.store.jobItems {
+		if item.JobID == jobID && item.Status == models.JobItemFailed {
+			item.Status = models.JobItemPending
+			item.Attempts = 0
+			item.NextAttemptAt = now
+			item.LastError = ""
+			item.UpdatedAt = now
+			requeued++
+		}
+	}
+	if requeued == 0 {
+		return repository.ErrJobNotFound
+	}
+
+	job.Status = models.JobRunning
+	job.FinishedAt = nil
+	return nil
+}
+
+func (s *Store) filterJobItems(match func(item *models.JobItem) bool) []*models.JobItem {
+	items := make([]*models.JobItem, 0)
+	for _, item := range s.jobItems {
+		if match(item) {
+			items = append(items, copyJobItem(item))
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ItemID < items[j].ItemID
+	})
+	return items
+}
+
+func copyJob(job *models.Job) *models.Job {
+	c := *job
+	c.UserIDs = append([]string{}, job.UserIDs...)
+	if job.FinishedAt != nil {
+		finishedAt := *job.FinishedAt
+		c.FinishedAt = &finishedAt
+	}
+	return &c
+}
+
+func copyJobItem(item *models.JobItem) *models.JobItem {
+	c := *item
+	return &c
+}
@@ -0,0 +1,229 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+type PullRequestRepository struct {
+	store *Store
+}
+
+func NewPullRequestRepository(store *Store) *PullRequestRepository {
+	return &PullRequestRepository{store: store}
+}
+
+func (r *PullRequestRepository) Create(ctx context.Context, pr *models.PullRequest) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.pullRequests[pr.PullRequestID]; exists {
+		return repository.ErrPRExists
+	}
+
+	stored := copyPullRequest(pr)
+	now := r.store.now()
+	stored.CreatedAt = &now
+	stored.MergedAt = nil
+	stored.ClosedAt = nil
+	r.store.pullRequests[pr.PullRequestID] = stored
+	return nil
+}
+
+func (r *PullRequestRepository) GetByID(ctx context.Context, prID string) (*models.PullRequest, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	pr, ok := r.store.pullRequests[prID]
+	if !ok {
+		return nil, repository.ErrPRNotFound
+	}
+
+	c := copyPullRequest(pr)
+	c.Reviews = r.store.currentReviews(pr)
+	return c, nil
+}
+
+func (r *PullRequestRepository) SubmitReview(ctx context.Context, prID string, review *models.Review) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.pullRequests[prID]; !ok {
+		return repository.ErrPRNotFound
+	}
+
+	if r.store.reviews[prID] == nil {
+		r.store.reviews[prID] = make(map[string]models.Review)
+	}
+	r.store.reviews[prID][review.UserID] = *review
+	return nil
+}
+
+func (r *PullRequestRepository) Merge(ctx context.Context, prID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	pr, ok := r.store.pullRequests[prID]
+	if !ok {
+		return repository.ErrPRNotFound
+	}
+
+	pr.Status = models.StatusMerged
+	if pr.MergedAt == nil {
+		now := r.store.now()
+		pr.MergedAt = &now
+	}
+	return nil
+}
+
+func (r *PullRequestRepository) Close(ctx context.Context, prID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	pr, ok := r.store.pullRequests[prID]
+	if !ok {
+		return repository.ErrPRNotFound
+	}
+
+	pr.Status = models.StatusClosed
+	if pr.ClosedAt == nil {
+		now := r.store.now()
+		pr.ClosedAt = &now
+	}
+	return nil
+}
+
+func (r *PullRequestRepository) MarkOpen(ctx context.Context, prID string, reviewers, fallbackReviewers []string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	pr, ok := r.store.pullRequests[prID]
+	if !ok {
+		return repository.ErrPRNotFound
+	}
+
+	pr.Status = models.StatusOpen
+	pr.ClosedAt = nil
+	for _, reviewerID := range reviewers {
+		if !containsString(pr.AssignedReviewers, reviewerID) {
+			pr.AssignedReviewers = append(pr.AssignedReviewers, reviewerID)
+			if containsString(fallbackReviewers, reviewerID) {
+				pr.FallbackReviewers = append(pr.FallbackReviewers, reviewerID)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *PullRequestRepository) ReassignReviewer(ctx context.Context, prID, oldUserID, newUserID string, fallback bool) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	pr, ok := r.store.pullRequests[prID]
+	if !ok {
+		return repository.ErrPRNotFound
+	}
+
+	for i, reviewerID := range pr.AssignedReviewers {
+		if reviewerID == oldUserID {
+			pr.AssignedReviewers[i] = newUserID
+
+			fallbackReviewers := make([]string, 0, len(pr.FallbackReviewers))
+			for _, fallbackID := range pr.FallbackReviewers {
+				if fallbackID != oldUserID {
+					fallbackReviewers = append(fallbackReviewers, fallbackID)
+				}
+			}
+			if fallback {
+				fallbackReviewers = append(fallbackReviewers, newUserID)
+			}
+			pr.FallbackReviewers = fallbackReviewers
+			if len(pr.FallbackReviewers) == 0 {
+				pr.FallbackReviewers = nil
+			}
+			return nil
+		}
+	}
+	return repository.ErrNotAssigned
+}
+
+func (r *PullRequestRepository) GetPRsByReviewer(ctx context.Context, userID string) ([]*models.PullRequestShort, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var prs []*models.PullRequestShort
+	for _, pr := range r.store.sortedPullRequests() {
+		if !containsString(pr.AssignedReviewers, userID) {
+			continue
+		}
+		prs = append(prs, &models.PullRequestShort{
+			PullRequestID:   pr.PullRequestID,
+			PullRequestName: pr.PullRequestName,
+			AuthorID:        pr.AuthorID,
+			Status:          pr.Status,
+		})
+	}
+	return prs, nil
+}
+
+func (r *PullRequestRepository) GetOpenPRsWithReviewer(ctx context.Context, userID string) ([]*models.PullRequest, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var prs []*models.PullRequest
+	for _, pr := range r.store.sortedPullRequests() {
+		if pr.Status == models.StatusOpen && containsString(pr.AssignedReviewers, userID) {
+			prs = append(prs, copyPullRequest(pr))
+		}
+	}
+	return prs, nil
+}
+
+func (r *PullRequestRepository) GetMergedPRs(ctx context.Context, from, to time.Time) ([]*models.PullRequest, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var prs []*models.PullRequest
+	for _, pr := range r.store.sortedPullRequests() {
+		if pr.Status != models.StatusMerged || pr.MergedAt.Before(from) || !pr.MergedAt.Before(to) {
+			continue
+		}
+		c := copyPullRequest(pr)
+		c.ChangedFiles = nil
+		prs = append(prs, c)
+	}
+	return prs, nil
+}
+
+// sortedPullRequests returns PRs newest first, like the ORDER BY created_at DESC
+// used by the Postgres implementation.
+func (s *Store) sortedPullRequests() []*models.PullRequest {
+	prs := make([]*models.PullRequest, 0, len(s.pullRequests))
+	for _, pr := range s.pullRequests {
+		prs = append(prs, pr)
+	}
+	sort.Slice(prs, func(i, j int) bool {
+		if !prs[i].CreatedAt.Equal(*prs[j].CreatedAt) {
+			return prs[i].CreatedAt.After(*prs[j].CreatedAt)
+		}
+		return prs[i].PullRequestID < prs[j].PullRequestID
+	})
+	return prs
+}
+
+func (s *Store) currentReviews(pr *models.PullRequest) []models.Review {
+	var reviews []models.Review
+	for _, reviewerID := range pr.AssignedReviewers {
+		if review, ok := s.reviews[pr.PullRequestID][reviewerID]; ok {
+			reviews = append(reviews, review)
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool {
+		return reviews[i].SubmittedAt.Before(reviews[j].SubmittedAt)
+	})
+	return reviews
+}
@@ -0,0 +1,147 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+// newPRStore has pr-1, pr-2 and pr-3 by alice, created in that order, with
+// bob and carol reviewing the first two and carol the last one.
+func newPRStore(t *testing.T) *PullRequestRepository {
+	t.Helper()
+	store := newTestStore()
+	prs := NewPullRequestRepository(store)
+	addTeam(t, store, "backend", "alice", "bob", "carol", "dave")
+	for _, pr := range []*models.PullRequest{
+		{PullRequestID: "pr-1", PullRequestName: "First", AuthorID: "alice", Status: models.StatusOpen, AssignedReviewers: []string{"bob", "carol"}},
+		{PullRequestID: "pr-2", PullRequestName: "Second", AuthorID: "alice", Status: models.StatusOpen, AssignedReviewers: []string{"bob", "carol"}},
+		{PullRequestID: "pr-3", PullRequestName: "Third", AuthorID: "alice", Status: models.StatusOpen, AssignedReviewers: []string{"carol"}},
+	} {
+		if err := prs.Create(context.Background(), pr); err != nil {
+			t.Fatalf("Create %s: %v", pr.PullRequestID, err)
+		}
+	}
+	return prs
+}
+
+func TestPullRequestCreate(t *testing.T) {
+	ctx := context.Background()
+	prs := newPRStore(t)
+
+	if err := prs.Create(ctx, &models.PullRequest{PullRequestID: "pr-1", AuthorID: "bob"}); err != repository.ErrPRExists {
+		t.Errorf("duplicate PR error = %v, want %v", err, repository.ErrPRExists)
+	}
+	if _, err := prs.GetByID(ctx, "pr-9"); err != repository.ErrPRNotFound {
+		t.Errorf("unknown PR error = %v, want %v", err, repository.ErrPRNotFound)
+	}
+
+	pr, err := prs.GetByID(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if pr.CreatedAt == nil || pr.MergedAt != nil || pr.Status != models.StatusOpen {
+		t.Errorf("pr-1 = %+v, want an open PR stamped with its creation time", pr)
+	}
+
+	// The reviewers returned are a copy of the stored ones.
+	pr.AssignedReviewers[0] = "dave"
+	if stored, _ := prs.GetByID(ctx, "pr-1"); !reflect.DeepEqual(stored.AssignedReviewers, []string{"bob", "carol"}) {
+		t.Errorf("stored reviewers = %v, want them unchanged by the caller", stored.AssignedReviewers)
+	}
+}
+
+func TestPullRequestMerge(t *testing.T) {
+	ctx := context.Background()
+	prs := newPRStore(t)
+
+	if err := prs.Merge(ctx, "pr-9"); err != repository.ErrPRNotFound {
+		t.Errorf("unknown PR error = %v, want %v", err, repository.ErrPRNotFound)
+	}
+	if err := prs.Merge(ctx, "pr-1"); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	merged, err := prs.GetByID(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if merged.Status != models.StatusMerged || merged.MergedAt == nil {
+		t.Fatalf("pr-1 = %+v, want it merged", merged)
+	}
+
+	// Merging again keeps the first merge time, like COALESCE(merged_at, NOW()).
+	if err := prs.Merge(ctx, "pr-1"); err != nil {
+		t.Fatalf("Merge again: %v", err)
+	}
+	again, err := prs.GetByID(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if !again.MergedAt.Equal(*merged.MergedAt) {
+		t.Errorf("merged at = %v after merging again, want %v", again.MergedAt, merged.MergedAt)
+	}
+}
+
+func TestPullRequestReassignReviewer(t *testing.T) {
+	ctx := context.Background()
+	prs := newPRStore(t)
+
+	if err := prs.ReassignReviewer(ctx, "pr-9", "bob", "dave", false); err != repository.ErrPRNotFound {
+		t.Errorf("unknown PR error = %v, want %v", err, repository.ErrPRNotFound)
+	}
+	if err := prs.ReassignReviewer(ctx, "pr-3", "bob", "dave", false); err != repository.ErrNotAssigned {
+		t.Errorf("unassigned reviewer error = %v, want %v", err, repository.ErrNotAssigned)
+	}
+
+	if err := prs.ReassignReviewer(ctx, "pr-1", "bob", "dave", false); err != nil {
+		t.Fatalf("ReassignReviewer: %v", err)
+	}
+	pr, err := prs.GetByID(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if want := []string{"dave", "carol"}; !reflect.DeepEqual(pr.AssignedReviewers, want) {
+		t.Errorf("reviewers = %v, want %v", pr.AssignedReviewers, want)
+	}
+}
+
+func TestPullRequestsByReviewer(t *testing.T) {
+	ctx := context.Background()
+	prs := newPRStore(t)
+	if err := prs.Merge(ctx, "pr-2"); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+
+	// Both lists are newest first, as ordered by created_at DESC in Postgres.
+	reviews, err := prs.GetPRsByReviewer(ctx, "carol")
+	if err != nil {
+		t.Fatalf("GetPRsByReviewer: %v", err)
+	}
+	want := []*models.PullRequestShort{
+		{PullRequestID: "pr-3", PullRequestName: "Third", AuthorID: "alice", Status: models.StatusOpen},
+		{PullRequestID: "pr-2", PullRequestName: "Second", AuthorID: "alice", Status: models.StatusMerged},
+		{PullRequestID: "pr-1", PullRequestName: "First", AuthorID: "alice", Status: models.StatusOpen},
+	}
+	if !reflect.DeepEqual(reviews, want) {
+		t.Errorf("GetPRsByReviewer(carol) = %+v, want %+v", reviews, want)
+	}
+
+	open, err := prs.GetOpenPRsWithReviewer(ctx, "carol")
+	if err != nil {
+		t.Fatalf("GetOpenPRsWithReviewer: %v", err)
+	}
+	var openIDs []string
+	for _, pr := range open {
+		openIDs = append(openIDs, pr.PullRequestID)
+	}
+	if want := []string{"pr-3", "pr-1"}; !reflect.DeepEqual(openIDs, want) {
+		t.Errorf("GetOpenPRsWithReviewer(carol) = %v, want %v", openIDs, want)
+	}
+
+	if none, err := prs.GetPRsByReviewer(ctx, "dave"); err != nil || len(none) != 0 {
+		t.Errorf("GetPRsByReviewer(dave) = %v, %v, want none", none, err)
+	}
+}
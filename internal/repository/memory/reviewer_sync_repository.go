@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+type ReviewerSyncRepository struct {
+	store *Store
+}
+
+func NewReviewerSyncRepository(store *Store) *ReviewerSyncRepository {
+	return &ReviewerSyncRepository{store: store}
+}
+
+func (r *ReviewerSyncRepository) Create(ctx context.Context, s *models.ReviewerSync) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.pullRequests[s.PullRequestID]; !ok {
+		return repository.ErrPRNotFound
+	}
+
+	s.SyncID = r.store.nextID()
+	s.CreatedAt = r.store.now()
+	r.store.reviewerSyncs[s.SyncID] = copyReviewerSync(s)
+	return nil
+}
+
+func (r *ReviewerSyncRepository) GetByID(ctx context.Context, syncID int64) (*models.ReviewerSync, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s, ok := r.store.reviewerSyncs[syncID]
+	if !ok {
+		return nil, repository.ErrSyncNotFound
+	}
+	return copyReviewerSync(s), nil
+}
+
+func (r *ReviewerSyncRepository) GetByPullRequest(ctx context.Context, prID string) ([]*models.ReviewerSync, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	syncs := make([]*models.ReviewerSync, 0)
+	for _, s := range r.store.reviewerSyncs {
+		if s.PullRequestID == prID {
+			syncs = append(syncs, copyReviewerSync(s))
+		}
+	}
+	sort.Slice(syncs, func(i, j int) bool {
+		return syncs[i].SyncID < syncs[j].SyncID
+	})
+	return syncs, nil
+}
+
+func (r *ReviewerSyncRepository) UpdateStatus(ctx context.Context, s *models.ReviewerSync) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.reviewerSyncs[s.SyncID]
+	if !ok {
+		return repository.ErrSyncNotFound
+	}
+	stored.Status = s.Status
+	stored.Error = s.Error
+	stored.PushedAt = nil
+	if s.PushedAt != nil {
+		pushedAt := *s.PushedAt
+		stored.PushedAt = &pushedAt
+	}
+	return nil
+}
+
+func copyReviewerSync(s *models.ReviewerSync) *models.ReviewerSync {
+	c := *s
+	c.Reviewers = append([]string{}, s.Reviewers...)
+	if s.PushedAt != nil {
+		pushedAt := *s.PushedAt
+		c.PushedAt = &pushedAt
+	}
+	return &c
+}
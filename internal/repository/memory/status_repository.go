@@ -0,0 +1,23 @@
+package memory
+
+import (
+	"context"
+
+	"pr-reviewer-service/internal/repository"
+)
+
+// StatusRepository reports the in-memory storage as always available. Its
+// schema is the one the code is built with, so it is always up to date.
+type StatusRepository struct{}
+
+func NewStatusRepository() *StatusRepository {
+	return &StatusRepository{}
+}
+
+func (r *StatusRepository) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (r *StatusRepository) SchemaVersion(ctx context.Context) (int64, bool, error) {
+	return repository.SchemaVersion, false, nil
+}
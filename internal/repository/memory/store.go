@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+var (
+	_ repository.UserRepository         = (*UserRepository)(nil)
+	_ repository.TeamRepository         = (*TeamRepository)(nil)
+	_ repository.PullRequestRepository  = (*PullRequestRepository)(nil)
+	_ repository.AvailabilityRepository = (*AvailabilityRepository)(nil)
+	_ repository.EventRepository        = (*EventRepository)(nil)
+	_ repository.WebhookRepository      = (*WebhookRepository)(nil)
+	_ repository.UserMappingRepository  = (*UserMappingRepository)(nil)
+	_ repository.ReviewerSyncRepository = (*ReviewerSyncRepository)(nil)
+	_ repository.JobRepository          = (*JobRepository)(nil)
+	_ repository.Transactor             = (*Transactor)(nil)
+	_ repository.StatusRepository       = (*StatusRepository)(nil)
+)
+
+// Store keeps all entities in process memory. Repositories created from the
+// same Store share its data, so it plays the role of a single database.
+type Store struct {
+	mu            sync.RWMutex
+	users         map[string]*models.User
+	teams         map[string]bool
+	teamSettings  map[string]*models.TeamSettings
+	codeOwners    map[string]*models.TeamCodeOwners
+	pullRequests  map[string]*models.PullRequest
+	reviews       map[string]map[string]models.Review
+	availability  map[int64]*models.Availability
+	events        []*models.Event
+	subscriptions map[int64]*models.WebhookSubscription
+	deliveries    map[int64]*models.WebhookDelivery
+	userMappings  map[mappingKey]*models.UserMapping
+	reviewerSyncs map[int64]*models.ReviewerSync
+	jobs          map[int64]*models.Job
+	jobItems      map[int64]*models.JobItem
+	lastID        int64
+	now           func() time.Time
+}
+
+func NewStore() *Store {
+	return &Store{
+		users:         make(map[string]*models.User),
+		teams:         make(map[string]bool),
+		teamSettings:  make(map[string]*models.TeamSettings),
+		codeOwners:    make(map[string]*models.TeamCodeOwners),
+		pullRequests:  make(map[string]*models.PullRequest),
+		reviews:       make(map[string]map[string]models.Review),
+		availability:  make(map[int64]*models.Availability),
+		subscriptions: make(map[int64]*models.WebhookSubscription),
+		deliveries:    make(map[int64]*models.WebhookDelivery),
+		userMappings:  make(map[mappingKey]*models.UserMapping),
+		reviewerSyncs: make(map[int64]*models.ReviewerSync),
+		jobs:          make(map[int64]*models.Job),
+		jobItems:      make(map[int64]*models.JobItem),
+		now:           time.Now,
+	}
+}
+
+func copyUser(user *models.User) *models.User {
+	c := *user
+	c.MaxOpenReviews = copyIntPtr(user.MaxOpenReviews)
+	return &c
+}
+
+func copyTeamSettings(settings *models.TeamSettings) *models.TeamSettings {
+	c := *settings
+	c.FallbackTeams = append([]string{}, settings.FallbackTeams...)
+	return &c
+}
+
+func copyIntPtr(value *int) *int {
+	if value == nil {
+		return nil
+	}
+	c := *value
+	return &c
+}
+
+func copyPullRequest(pr *models.PullRequest) *models.PullRequest {
+	c := *pr
+	if pr.AssignedReviewers != nil {
+		c.AssignedReviewers = append([]string(nil), pr.AssignedReviewers...)
+	}
+	if pr.FallbackReviewers != nil {
+		c.FallbackReviewers = append([]string(nil), pr.FallbackReviewers...)
+	}
+	if pr.ChangedFiles != nil {
+		c.ChangedFiles = append([]string(nil), pr.ChangedFiles...)
+	}
+	if pr.CreatedAt != nil {
+		createdAt := *pr.CreatedAt
+		c.CreatedAt = &createdAt
+	}
+	if pr.MergedAt != nil {
+		mergedAt := *pr.MergedAt
+		c.MergedAt = &mergedAt
+	}
+	if pr.ClosedAt != nil {
+		closedAt := *pr.ClosedAt
+		c.ClosedAt = &closedAt
+	}
+	return &c
+}
+
+// nextID emulates a BIGSERIAL sequence shared by all tables of the store.
+func (s *Store) nextID() int64 {
+	s.lastID++
+	return s.lastID
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
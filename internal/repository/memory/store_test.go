@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+// newTestStore returns a store whose clock moves a minute forward on every
+// reading, so that records are ordered by the time they were written.
+func newTestStore() *Store {
+	store := NewStore()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+	return store
+}
+
+// addTeam creates teamName with active members of the given user ids.
+func addTeam(t *testing.T, store *Store, teamName string, userIDs ...string) {
+	t.Helper()
+	team := &models.Team{TeamName: teamName}
+	for _, userID := range userIDs {
+		team.Members = append(team.Members, models.TeamMember{UserID: userID, Username: userID, IsActive: true})
+	}
+	if err := NewTeamRepository(store).Create(context.Background(), team); err != nil {
+		t.Fatalf("Create team %s: %v", teamName, err)
+	}
+}
+
+func userIDs(users []*models.User) []string {
+	ids := make([]string, len(users))
+	for i, user := range users {
+		ids[i] = user.UserID
+	}
+	return ids
+}
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+type TeamRepository struct {
+	store *Store
+}
+
+func NewTeamRepository(store *Store) *TeamRepository {
+	return &TeamRepository{store: store}
+}
+
+func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.teams[team.TeamName] {
+		return repository.ErrTeamExists
+	}
+
+	r.store.teams[team.TeamName] = true
+	for _, member := range team.Members {
+		if member.UserID == "" {
+			continue
+		}
+		r.store.users[member.UserID] = &models.User{
+			UserID:         member.UserID,
+			Username:       member.Username,
+			TeamName:       team.TeamName,
+			IsActive:       member.IsActive,
+			MaxOpenReviews: copyIntPtr(member.MaxOpenReviews),
+		}
+	}
+	return nil
+}
+
+func (r *TeamRepository) GetByName(ctx context.Context, teamName string) (*models.Team, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if !r.store.teams[teamName] {
+		return nil, repository.ErrTeamNotFound
+	}
+
+	members := make([]models.TeamMember, 0)
+	for _, user := range r.store.sortedUsers() {
+		if user.TeamName != teamName {
+			continue
+		}
+		members = append(members, models.TeamMember{
+			UserID:         user.UserID,
+			Username:       user.Username,
+			IsActive:       user.IsActive,
+			MaxOpenReviews: copyIntPtr(user.MaxOpenReviews),
+		})
+	}
+
+	return &models.Team{
+		TeamName: teamName,
+		Members:  members,
+	}, nil
+}
+
+func (r *TeamRepository) GetSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if !r.store.teams[teamName] {
+		return nil, repository.ErrTeamNotFound
+	}
+
+	settings, ok := r.store.teamSettings[teamName]
+	if !ok {
+		return models.DefaultTeamSettings(teamName), nil
+	}
+	return copyTeamSettings(settings), nil
+}
+
+func (r *TeamRepository) UpdateSettings(ctx context.Context, settings *models.TeamSettings) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.teams[settings.TeamName] {
+		return repository.ErrTeamNotFound
+	}
+
+	r.store.teamSettings[settings.TeamName] = copyTeamSettings(settings)
+	return nil
+}
+
+func (r *TeamRepository) GetCodeOwners(ctx context.Context, teamName string) (*models.TeamCodeOwners, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if !r.store.teams[teamName] {
+		return nil, repository.ErrTeamNotFound
+	}
+
+	codeOwners, ok := r.store.codeOwners[teamName]
+	if !ok {
+		return &models.TeamCodeOwners{TeamName: teamName}, nil
+	}
+	c := *codeOwners
+	return &c, nil
+}
+
+func (r *TeamRepository) SetCodeOwners(ctx context.Context, codeOwners *models.TeamCodeOwners) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.teams[codeOwners.TeamName] {
+		return repository.ErrTeamNotFound
+	}
+
+	now := r.store.now()
+	codeOwners.UpdatedAt = &now
+	c := *codeOwners
+	r.store.codeOwners[codeOwners.TeamName] = &c
+	return nil
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+func TestTeamCreate(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore()
+	teams := NewTeamRepository(store)
+	users := NewUserRepository(store)
+	addTeam(t, store, "backend", "carol", "alice", "bob")
+
+	if err := teams.Create(ctx, &models.Team{TeamName: "backend"}); err != repository.ErrTeamExists {
+		t.Errorf("duplicate team error = %v, want %v", err, repository.ErrTeamExists)
+	}
+	if _, err := teams.GetByName(ctx, "frontend"); err != repository.ErrTeamNotFound {
+		t.Errorf("unknown team error = %v, want %v", err, repository.ErrTeamNotFound)
+	}
+
+	// Members of another team move over, like the upsert of the Postgres
+	// implementation; members without an id are skipped.
+	err := teams.Create(ctx, &models.Team{TeamName: "frontend", Members: []models.TeamMember{
+		{UserID: "bob", Username: "Bob", IsActive: false},
+		{UserID: "", Username: "nobody", IsActive: true},
+	}})
+	if err != nil {
+		t.Fatalf("Create frontend: %v", err)
+	}
+	bob, err := users.GetByID(ctx, "bob")
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if bob.TeamName != "frontend" || bob.Username != "Bob" || bob.IsActive {
+		t.Errorf("bob = %+v, want bob moved to frontend as given", bob)
+	}
+
+	backend, err := teams.GetByName(ctx, "backend")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	want := []models.TeamMember{
+		{UserID: "alice", Username: "alice", IsActive: true},
+		{UserID: "carol", Username: "carol", IsActive: true},
+	}
+	if !reflect.DeepEqual(backend.Members, want) {
+		t.Errorf("backend members = %+v, want %+v", backend.Members, want)
+	}
+
+	empty, err := teams.GetByName(ctx, "frontend")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	if len(empty.Members) != 1 {
+		t.Errorf("frontend members = %+v, want bob only", empty.Members)
+	}
+}
+
+func TestTeamSettings(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore()
+	teams := NewTeamRepository(store)
+	addTeam(t, store, "backend", "alice")
+
+	settings, err := teams.GetSettings(ctx, "backend")
+	if err != nil {
+		t.Fatalf("GetSettings: %v", err)
+	}
+	if !reflect.DeepEqual(settings, models.DefaultTeamSettings("backend")) {
+		t.Errorf("settings = %+v, want the defaults before any update", settings)
+	}
+
+	settings.ReviewerCount = 3
+	if err := teams.UpdateSettings(ctx, settings); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	// The stored settings do not change along with the caller's copy.
+	settings.ReviewerCount = 5
+	stored, err := teams.GetSettings(ctx, "backend")
+	if err != nil {
+		t.Fatalf("GetSettings: %v", err)
+	}
+	if stored.ReviewerCount != 3 {
+		t.Errorf("reviewer count = %d, want 3", stored.ReviewerCount)
+	}
+
+	if _, err := teams.GetSettings(ctx, "frontend"); err != repository.ErrTeamNotFound {
+		t.Errorf("GetSettings unknown team error = %v, want %v", err, repository.ErrTeamNotFound)
+	}
+	if err := teams.UpdateSettings(ctx, models.DefaultTeamSettings("frontend")); err != repository.ErrTeamNotFound {
+		t.Errorf("UpdateSettings unknown team error = %v, want %v", err, repository.ErrTeamNotFound)
+	}
+}
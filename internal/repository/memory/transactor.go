@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+// Transactor emulates transactions by running the work on a copy of the store.
+// An atomic transaction holds the store lock until it finishes, so fn must not
+// use repositories other than the ones it is given.
+type Transactor struct {
+	store *Store
+}
+
+func NewTransactor(store *Store) *Transactor {
+	return &Transactor{store: store}
+}
+
+func (t *Transactor) Atomic(ctx context.Context, fn func(repos *repository.Repositories) error) error {
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	c := t.store.copyTables()
+	if err := fn(c.repositories()); err != nil {
+		return err
+	}
+	t.store.replaceTables(c)
+	return nil
+}
+
+func (t *Transactor) DryRun(ctx context.Context, fn func(repos *repository.Repositories) error) error {
+	return fn(t.store.snapshot().repositories())
+}
+
+func (s *Store) repositories() *repository.Repositories {
+	return &repository.Repositories{
+		Users:        NewUserRepository(s),
+		Teams:        NewTeamRepository(s),
+		PullRequests: NewPullRequestRepository(s),
+		Events:       NewEventRepository(s),
+		Jobs:         NewJobRepository(s),
+	}
+}
+
+func (s *Store) snapshot() *Store {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.copyTables()
+}
+
+// copyTables copies the tables used by transactional repositories. Changes
+// made to the copy are not visible in the store. The caller holds the lock.
+func (s *Store) copyTables() *Store {
+	c := NewStore()
+	c.lastID = s.lastID
+	c.now = s.now
+	for userID, user := range s.users {
+		c.users[userID] = copyUser(user)
+	}
+	for teamName := range s.teams {
+		c.teams[teamName] = true
+	}
+	for teamName, settings := range s.teamSettings {
+		c.teamSettings[teamName] = copyTeamSettings(settings)
+	}
+	for teamName, codeOwners := range s.codeOwners {
+		copied := *codeOwners
+		c.codeOwners[teamName] = &copied
+	}
+	for prID, pr := range s.pullRequests {
+		c.pullRequests[prID] = copyPullRequest(pr)
+	}
+	for prID, reviews := range s.reviews {
+		c.reviews[prID] = make(map[string]models.Review, len(reviews))
+		for userID, review := range reviews {
+			c.reviews[prID][userID] = review
+		}
+	}
+	for availabilityID, a := range s.availability {
+		c.availability[availabilityID] = copyAvailability(a)
+	}
+	c.events = append(c.events, s.events...)
+	for jobID, job := range s.jobs {
+		c.jobs[jobID] = copyJob(job)
+	}
+	for itemID, item := range s.jobItems {
+		c.jobItems[itemID] = copyJobItem(item)
+	}
+	return c
+}
+
+// replaceTables commits a copy made by copyTables. The caller holds the lock.
+func (s *Store) replaceTables(c *Store) {
+	s.users = c.users
+	s.teams = c.teams
+	s.teamSettings = c.teamSettings
+	s.codeOwners = c.codeOwners
+	s.pullRequests = c.pullRequests
+	s.reviews = c.reviews
+	s.availability = c.availability
+	s.events = c.events
+	s.jobs = c.jobs
+	s.jobItems = c.jobItems
+	s.lastID = c.lastID
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+type UserMappingRepository struct {
+	store *Store
+}
+
+func NewUserMappingRepository(store *Store) *UserMappingRepository {
+	return &UserMappingRepository{store: store}
+}
+
+type mappingKey struct {
+	provider string
+	login    string
+}
+
+func (r *UserMappingRepository) Set(ctx context.Context, m *models.UserMapping) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[m.UserID]; !ok {
+		return repository.ErrUserNotFound
+	}
+	c := *m
+	r.store.userMappings[mappingKey{m.Provider, m.Login}] = &c
+	return nil
+}
+
+func (r *UserMappingRepository) Get(ctx context.Context, provider, login string) (*models.UserMapping, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	m, ok := r.store.userMappings[mappingKey{provider, login}]
+	if !ok {
+		return nil, repository.ErrMappingNotFound
+	}
+	c := *m
+	return &c, nil
+}
+
+func (r *UserMappingRepository) GetByProvider(ctx context.Context, provider string) ([]*models.UserMapping, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	mappings := make([]*models.UserMapping, 0)
+	for key, m := range r.store.userMappings {
+		if key.provider == provider {
+			c := *m
+			mappings = append(mappings, &c)
+		}
+	}
+	sort.Slice(mappings, func(i, j int) bool {
+		return mappings[i].Login < mappings[j].Login
+	})
+	return mappings, nil
+}
+
+func (r *UserMappingRepository) Delete(ctx context.Context, provider, login string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := mappingKey{provider, login}
+	if _, ok := r.store.userMappings[key]; !ok {
+		return repository.ErrMappingNotFound
+	}
+	delete(r.store.userMappings, key)
+	return nil
+}
@@ -0,0 +1,200 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+type UserRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{store: store}
+}
+
+func (r *UserRepository) CreateOrUpdate(ctx context.Context, user *models.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.users[user.UserID] = copyUser(user)
+	return nil
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, userID string) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.users[userID]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	return copyUser(user), nil
+}
+
+func (r *UserRepository) SetIsActive(ctx context.Context, userID string, isActive bool) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[userID]
+	if !ok {
+		return repository.ErrUserNotFound
+	}
+	user.IsActive = isActive
+	return nil
+}
+
+func (r *UserRepository) SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[userID]
+	if !ok {
+		return repository.ErrUserNotFound
+	}
+	user.MaxOpenReviews = copyIntPtr(maxOpenReviews)
+	return nil
+}
+
+func (r *UserRepository) GetActiveUsersByTeam(ctx context.Context, teamName, excludeUserID string) ([]*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	now := r.store.now()
+	var users []*models.User
+	for _, user := range r.store.sortedUsers() {
+		if user.TeamName == teamName && user.IsActive && user.UserID != excludeUserID && !r.store.isAway(user.UserID, now) {
+			users = append(users, copyUser(user))
+		}
+	}
+	return users, nil
+}
+
+func (r *UserRepository) GetUsersByTeam(ctx context.Context, teamName string) ([]*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var users []*models.User
+	for _, user := range r.store.sortedUsers() {
+		if user.TeamName == teamName {
+			users = append(users, copyUser(user))
+		}
+	}
+	return users, nil
+}
+
+func (r *UserRepository) BulkSetIsActive(ctx context.Context, userIDs []string, isActive bool) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, userID := range userIDs {
+		if user, ok := r.store.users[userID]; ok {
+			user.IsActive = isActive
+		}
+	}
+	return nil
+}
+
+func (r *UserRepository) GetReviewerCount(ctx context.Context, userID string) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	count := 0
+	for _, pr := range r.store.pullRequests {
+		if containsString(pr.AssignedReviewers, userID) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *UserRepository) GetOpenReviewCounts(ctx context.Context, userIDs []string) (map[string]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[string]int, len(userIDs))
+	for _, pr := range r.store.pullRequests {
+		if pr.Status != models.StatusOpen {
+			continue
+		}
+		for _, userID := range userIDs {
+			if containsString(pr.AssignedReviewers, userID) {
+				counts[userID]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+func (r *UserRepository) GetAuthoredPRCount(ctx context.Context, userID string) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	count := 0
+	for _, pr := range r.store.pullRequests {
+		if pr.AuthorID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *UserRepository) GetAllUsersStats(ctx context.Context, filter models.StatsFilter) ([]*models.UserStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	inPeriod := func(t time.Time) bool {
+		return !t.Before(filter.From) && t.Before(filter.To)
+	}
+
+	var stats []*models.UserStats
+	for _, user := range r.store.sortedUsers() {
+		if filter.TeamName != "" && user.TeamName != filter.TeamName {
+			continue
+		}
+		s := &models.UserStats{
+			UserID:         user.UserID,
+			Username:       user.Username,
+			TeamName:       user.TeamName,
+			IsActive:       user.IsActive,
+			MaxOpenReviews: copyIntPtr(user.MaxOpenReviews),
+		}
+		for _, pr := range r.store.pullRequests {
+			if containsString(pr.AssignedReviewers, user.UserID) {
+				if inPeriod(*pr.CreatedAt) {
+					s.AssignedAsReviewerCount++
+				}
+				if pr.Status == models.StatusOpen {
+					s.OpenReviewCount++
+				}
+			}
+			if pr.AuthorID == user.UserID && inPeriod(*pr.CreatedAt) {
+				s.AuthoredPRCount++
+			}
+			if review, ok := r.store.reviews[pr.PullRequestID][user.UserID]; ok && inPeriod(review.SubmittedAt) {
+				s.ReviewCount++
+				if s.FirstReviewAt == nil || review.SubmittedAt.Before(*s.FirstReviewAt) {
+					submittedAt := review.SubmittedAt
+					s.FirstReviewAt = &submittedAt
+				}
+			}
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+func (s *Store) sortedUsers() []*models.User {
+	users := make([]*models.User, 0, len(s.users))
+	for _, user := range s.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].UserID < users[j].UserID
+	})
+	return users
+}
@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+func TestUserNotFound(t *testing.T) {
+	ctx := context.Background()
+	users := NewUserRepository(newTestStore())
+
+	if _, err := users.GetByID(ctx, "bob"); err != repository.ErrUserNotFound {
+		t.Errorf("GetByID error = %v, want %v", err, repository.ErrUserNotFound)
+	}
+	if err := users.SetIsActive(ctx, "bob", false); err != repository.ErrUserNotFound {
+		t.Errorf("SetIsActive error = %v, want %v", err, repository.ErrUserNotFound)
+	}
+	// Bulk updates skip unknown users, like an UPDATE matching no rows.
+	if err := users.BulkSetIsActive(ctx, []string{"bob"}, false); err != nil {
+		t.Errorf("BulkSetIsActive error = %v, want nil", err)
+	}
+}
+
+func TestUsersByTeam(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore()
+	users := NewUserRepository(store)
+	addTeam(t, store, "backend", "dave", "bob", "alice", "carol")
+	addTeam(t, store, "frontend", "erin")
+	if err := users.BulkSetIsActive(ctx, []string{"carol", "erin", "nobody"}, false); err != nil {
+		t.Fatalf("BulkSetIsActive: %v", err)
+	}
+
+	all, err := users.GetUsersByTeam(ctx, "backend")
+	if err != nil {
+		t.Fatalf("GetUsersByTeam: %v", err)
+	}
+	if got, want := userIDs(all), []string{"alice", "bob", "carol", "dave"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetUsersByTeam = %v, want %v", got, want)
+	}
+
+	active, err := users.GetActiveUsersByTeam(ctx, "backend", "alice")
+	if err != nil {
+		t.Fatalf("GetActiveUsersByTeam: %v", err)
+	}
+	if got, want := userIDs(active), []string{"bob", "dave"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetActiveUsersByTeam = %v, want %v", got, want)
+	}
+
+	if none, err := users.GetActiveUsersByTeam(ctx, "frontend", ""); err != nil || len(none) != 0 {
+		t.Errorf("GetActiveUsersByTeam(frontend) = %v, %v, want no users", userIDs(none), err)
+	}
+}
+
+func TestUserCopies(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore()
+	users := NewUserRepository(store)
+	addTeam(t, store, "backend", "alice")
+
+	user, err := users.GetByID(ctx, "alice")
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	user.IsActive = false
+	user.TeamName = "frontend"
+
+	stored, err := users.GetByID(ctx, "alice")
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if !stored.IsActive || stored.TeamName != "backend" {
+		t.Errorf("stored user = %+v, want it unchanged by the caller", stored)
+	}
+
+	if err := users.CreateOrUpdate(ctx, &models.User{UserID: "alice", Username: "Alice", TeamName: "backend"}); err != nil {
+		t.Fatalf("CreateOrUpdate: %v", err)
+	}
+	if stored, _ := users.GetByID(ctx, "alice"); stored.Username != "Alice" || stored.IsActive {
+		t.Errorf("updated user = %+v, want every field replaced", stored)
+	}
+}
+
+func TestReviewCounts(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore()
+	users := NewUserRepository(store)
+	prs := NewPullRequestRepository(store)
+	addTeam(t, store, "backend", "alice", "bob", "carol")
+
+	for _, pr := range []*models.PullRequest{
+		{PullRequestID: "pr-1", AuthorID: "alice", Status: models.StatusOpen, AssignedReviewers: []string{"bob", "carol"}},
+		{PullRequestID: "pr-2", AuthorID: "alice", Status: models.StatusOpen, AssignedReviewers: []string{"bob"}},
+		{PullRequestID: "pr-3", AuthorID: "bob", Status: models.StatusOpen, AssignedReviewers: []string{"carol"}},
+	} {
+		if err := prs.Create(ctx, pr); err != nil {
+			t.Fatalf("Create %s: %v", pr.PullRequestID, err)
+		}
+	}
+	if err := prs.Merge(ctx, "pr-2"); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+
+	// Open counts leave out merged PRs; the totals keep them.
+	open, err := users.GetOpenReviewCounts(ctx, []string{"alice", "bob", "carol"})
+	if err != nil {
+		t.Fatalf("GetOpenReviewCounts: %v", err)
+	}
+	if want := map[string]int{"bob": 1, "carol": 2}; !reflect.DeepEqual(open, want) {
+		t.Errorf("open review counts = %v, want %v", open, want)
+	}
+
+	for _, tt := range []struct {
+		userID            string
+		reviews, authored int
+	}{
+		{"alice", 0, 2},
+		{"bob", 2, 1},
+		{"carol", 2, 0},
+	} {
+		reviews, err := users.GetReviewerCount(ctx, tt.userID)
+		if err != nil {
+			t.Fatalf("GetReviewerCount: %v", err)
+		}
+		authored, err := users.GetAuthoredPRCount(ctx, tt.userID)
+		if err != nil {
+			t.Fatalf("GetAuthoredPRCount: %v", err)
+		}
+		if reviews != tt.reviews || authored != tt.authored {
+			t.Errorf("%s reviews %d, authored %d, want %d, %d", tt.userID, reviews, authored, tt.reviews, tt.authored)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+type WebhookRepository struct {
+	store *Store
+}
+
+func NewWebhookRepository(store *Store) *WebhookRepository {
+	return &WebhookRepository{store: store}
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	sub.SubscriptionID = r.store.nextID()
+	sub.CreatedAt = r.store.now()
+	r.store.subscriptions[sub.SubscriptionID] = copySubscription(sub)
+	return nil
+}
+
+func (r *WebhookRepository) GetSubscription(ctx context.Context, subscriptionID int64) (*models.WebhookSubscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	sub, ok := r.store.subscriptions[subscriptionID]
+	if !ok {
+		return nil, repository.ErrSubscriptionNotFound
+	}
+	return copySubscription(sub), nil
+}
+
+func (r *WebhookRepository) GetSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	subs := make([]*models.WebhookSubscription, 0, len(r.store.subscriptions))
+	for _, sub := range r.store.subscriptions {
+		subs = append(subs, copySubscription(sub))
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].SubscriptionID < subs[j].SubscriptionID
+	})
+	return subs, nil
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, subscriptionID int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.subscriptions[subscriptionID]; !ok {
+		return repository.ErrSubscriptionNotFound
+	}
+	delete(r.store.subscriptions, subscriptionID)
+	for id, d := range r.store.deliveries {
+		if d.SubscriptionID == subscriptionID {
+			delete(r.store.deliveries, id)
+		}
+	}
+	return nil
+}
+
+func (r *WebhookRepository) EnqueueDelivery(ctx context.Context, d *models.WebhookDelivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.subscriptions[d.SubscriptionID]; !ok {
+		return repository.ErrSubscriptionNotFound
+	}
+
+	now := r.store.now()
+	d.DeliveryID = r.store.nextID()
+	d.Status = models.DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = now
+	d.LastError = ""
+	d.CreatedAt = now
+	d.DeliveredAt = nil
+	r.store.deliveries[d.DeliveryID] = copyDelivery(d)
+	return nil
+}
+
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := r.store.now()
+	due := r.store.filterDeliveries(func(d *models.WebhookDelivery) bool {
+		return d.Status == models.DeliveryPending && !d.NextAttemptAt.After(now)
+	})
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	for _, d := range due {
+		d.NextAttemptAt = now.Add(lease)
+		r.store.deliveries[d.DeliveryID].NextAttemptAt = d.NextAttemptAt
+	}
+	return due, nil
+}
+
+func (r *WebhookRepository) SaveDeliveryAttempt(ctx context.Context, d *models.WebhookDelivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.deliveries[d.DeliveryID]; !ok {
+		return repository.ErrDeliveryNotFound
+	}
+	r.store.deliveries[d.DeliveryID] = copyDelivery(d)
+	return nil
+}
+
+func (r *WebhookRepository) GetDeadDeliveries(ctx context.Context) ([]*models.WebhookDelivery, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.filterDeliveries(func(d *models.WebhookDelivery) bool {
+		return d.Status == models.DeliveryDead
+	}), nil
+}
+
+func (r *WebhookRepository) RequeueDelivery(ctx context.Context, deliveryID int64) (*models.WebhookDelivery, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	d, ok := r.store.deliveries[deliveryID]
+	if !ok || d.Status != models.DeliveryDead {
+		return nil, repository.ErrDeliveryNotFound
+	}
+	d.Status = models.DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = r.store.now()
+	d.LastError = ""
+	return copyDelivery(d), nil
+}
+
+func (s *Store) filterDeliveries(match func(d *models.WebhookDelivery) bool) []*models.WebhookDelivery {
+	deliveries := make([]*models.WebhookDelivery, 0)
+	for _, d := range s.deliveries {
+		if match(d) {
+			deliveries = append(deliveries, copyDelivery(d))
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].DeliveryID < deliveries[j].DeliveryID
+	})
+	return deliveries
+}
+
+func copySubscription(sub *models.WebhookSubscription) *models.WebhookSubscription {
+	c := *sub
+	c.Events = append([]string{}, sub.Events...)
+	return &c
+}
+
+func copyDelivery(d *models.WebhookDelivery) *models.WebhookDelivery {
+	c := *d
+	c.Payload = append([]byte(nil), d.Payload...)
+	if d.DeliveredAt != nil {
+		deliveredAt := *d.DeliveredAt
+		c.DeliveredAt = &deliveredAt
+	}
+	return &c
+}
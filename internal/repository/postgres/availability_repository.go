@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+type AvailabilityRepository struct {
+	db conn
+}
+
+func NewAvailabilityRepository(db *sql.DB, queryTimeout time.Duration) *AvailabilityRepository {
+	return &AvailabilityRepository{db: conn{db: db, timeout: queryTimeout}}
+}
+
+const availabilityColumns = `availability_id, user_id, starts_at, ends_at, reason, reassign_reviews, reviews_reassigned_at`
+
+func scanAvailability(row rowScanner) (*models.Availability, error) {
+	var a models.Availability
+	var reassignedAt sql.NullTime
+	if err := row.Scan(&a.AvailabilityID, &a.UserID, &a.StartsAt, &a.EndsAt, &a.Reason, &a.ReassignReviews, &reassignedAt); err != nil {
+		return nil, err
+	}
+	if reassignedAt.Valid {
+		a.ReviewsReassignedAt = &reassignedAt.Time
+	}
+	return &a, nil
+}
+
+func (r *AvailabilityRepository) Create(ctx context.Context, a *models.Availability) error {
+	query := `INSERT INTO user_availability (user_id, starts_at, ends_at, reason, reassign_reviews)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING availability_id`
+	return bind(ctx, r.db).QueryRow(query, a.UserID, a.StartsAt, a.EndsAt, a.Reason, a.ReassignReviews).Scan(&a.AvailabilityID)
+}
+
+func (r *AvailabilityRepository) GetByUser(ctx context.Context, userID string) ([]*models.Availability, error) {
+	query := `SELECT ` + availabilityColumns + `
+		FROM user_availability
+		WHERE user_id = $1
+		ORDER BY starts_at`
+	return r.query(ctx, query, userID)
+}
+
+func (r *AvailabilityRepository) Delete(ctx context.Context, availabilityID int64) error {
+	result, err := bind(ctx, r.db).Exec(`DELETE FROM user_availability WHERE availability_id = $1`, availabilityID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return repository.ErrAvailabilityNotFound
+	}
+	return nil
+}
+
+func (r *AvailabilityRepository) GetStartedPendingReassignment(ctx context.Context) ([]*models.Availability, error) {
+	query := `SELECT ` + availabilityColumns + `
+		FROM user_availability
+		WHERE reassign_reviews = true AND reviews_reassigned_at IS NULL
+			AND starts_at <= NOW() AND ends_at > NOW()
+		ORDER BY starts_at`
+	return r.query(ctx, query)
+}
+
+func (r *AvailabilityRepository) MarkReviewsReassigned(ctx context.Context, availabilityID int64) error {
+	_, err := bind(ctx, r.db).Exec(
+		`UPDATE user_availability SET reviews_reassigned_at = NOW() WHERE availability_id = $1`,
+		availabilityID)
+	return err
+}
+
+func (r *AvailabilityRepository) query(ctx context.Context, query string, args ...interface{}) ([]*models.Availability, error) {
+	rows, err := bind(ctx, r.db).Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var windows []*models.Availability
+	for rows.Next() {
+		a, err := scanAvailability(rows)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, a)
+	}
+	return windows, rows.Err()
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+type EventRepository struct {
+	db conn
+}
+
+func NewEventRepository(db *sql.DB, queryTimeout time.Duration) *EventRepository {
+	return &EventRepository{db: conn{db: db, timeout: queryTimeout}}
+}
+
+func (r *EventRepository) Append(ctx context.Context, event *models.Event) error {
+	query := `INSERT INTO pr_events (event_type, pull_request_id, user_id, previous_user_id, actor, strategy, details)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), NULLIF($4, ''), $5, NULLIF($6, ''), NULLIF($7, ''))
+		RETURNING event_id, created_at`
+	return bind(ctx, r.db).QueryRow(query,
+		event.EventType, event.PullRequestID, event.UserID, event.PreviousUserID,
+		event.Actor, event.Strategy, event.Details,
+	).Scan(&event.EventID, &event.CreatedAt)
+}
+
+func (r *EventRepository) GetByPullRequest(ctx context.Context, prID string) ([]*models.Event, error) {
+	query := `SELECT event_id, event_type, pull_request_id, user_id, previous_user_id, actor, strategy, details, created_at
+		FROM pr_events
+		WHERE pull_request_id = $1
+		ORDER BY event_id`
+	return r.query(ctx, query, prID)
+}
+
+func (r *EventRepository) GetByUser(ctx context.Context, userID string) ([]*models.Event, error) {
+	query := `SELECT event_id, event_type, pull_request_id, user_id, previous_user_id, actor, strategy, details, created_at
+		FROM pr_events
+		WHERE user_id = $1 OR previous_user_id = $1 OR actor = $1
+		ORDER BY event_id`
+	return r.query(ctx, query, userID)
+}
+
+func (r *EventRepository) query(ctx context.Context, query string, args ...interface{}) ([]*models.Event, error) {
+	rows, err := bind(ctx, r.db).Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*models.Event, 0)
+	for rows.Next() {
+		var event models.Event
+		var prID, userID, previousUserID, strategy, details sql.NullString
+		err := rows.Scan(&event.EventID, &event.EventType, &prID, &userID, &previousUserID,
+			&event.Actor, &strategy, &details, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		event.PullRequestID = prID.String
+		event.UserID = userID.String
+		event.PreviousUserID = previousUserID.String
+		event.Strategy = strategy.String
+		event.Details = details.String
+		events = append(events, &event)
+	}
+	return events, rows.Err()
+}
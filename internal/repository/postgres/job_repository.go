@@ -0,0 +1,197 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+type JobRepository struct {
+	db conn
+}
+
+func NewJobRepository(db *sql.DB, queryTimeout time.Duration) *JobRepository {
+	return &JobRepository{db: conn{db: db, timeout: queryTimeout}}
+}
+
+const jobItemColumns = `i.item_id, i.job_id, i.pull_request_id, i.user_id, i.status, i.attempts, i.next_attempt_at,
+	i.last_error, i.replaced_by, i.updated_at, j.actor`
+
+func scanJobItem(row rowScanner) (*models.JobItem, error) {
+	var item models.JobItem
+	err := row.Scan(&item.ItemID, &item.JobID, &item.PullRequestID, &item.UserID, &item.Status, &item.Attempts,
+		&item.NextAttemptAt, &item.LastError, &item.ReplacedBy, &item.UpdatedAt, &item.Actor)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *JobRepository) CreateJob(ctx context.Context, job *models.Job) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	job.Status = models.JobRunning
+	job.FinishedAt = nil
+	if len(job.Items) == 0 {
+		job.Status = models.JobCompleted
+		job.FinishedAt = &now
+	}
+
+	err = tx.QueryRow(
+		`INSERT INTO jobs (job_type, team_name, user_ids, actor, status, created_at, finished_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING job_id`,
+		job.JobType, job.TeamName, pq.Array(job.UserIDs), job.Actor, job.Status, now, job.FinishedAt,
+	).Scan(&job.JobID)
+	if err != nil {
+		return err
+	}
+	job.CreatedAt = now
+
+	for _, item := range job.Items {
+		item.JobID = job.JobID
+		item.Status = models.JobItemPending
+		item.Actor = job.Actor
+		err = tx.QueryRow(
+			`INSERT INTO job_items (job_id, pull_request_id, user_id)
+			VALUES ($1, $2, $3)
+			RETURNING item_id, attempts, next_attempt_at, updated_at`,
+			job.JobID, item.PullRequestID, item.UserID,
+		).Scan(&item.ItemID, &item.Attempts, &item.NextAttemptAt, &item.UpdatedAt)
+		if err != nil {
+			return err
+		}
+	}
+	job.Count()
+
+	return tx.Commit()
+}
+
+func (r *JobRepository) GetJob(ctx context.Context, jobID int64) (*models.Job, error) {
+	var job models.Job
+	var userIDs pq.StringArray
+	var finishedAt sql.NullTime
+	err := bind(ctx, r.db).QueryRow(
+		`SELECT job_id, job_type, team_name, user_ids, actor, status, created_at, finished_at
+		FROM jobs WHERE job_id = $1`, jobID,
+	).Scan(&job.JobID, &job.JobType, &job.TeamName, &userIDs, &job.Actor, &job.Status, &job.CreatedAt, &finishedAt)
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	job.UserIDs = []string(userIDs)
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+
+	job.Items, err = r.queryItems(ctx, `SELECT `+jobItemColumns+`
+		FROM job_items i
+		INNER JOIN jobs j ON j.job_id = i.job_id
+		WHERE i.job_id = $1
+		ORDER BY i.item_id`, jobID)
+	if err != nil {
+		return nil, err
+	}
+	job.Count()
+	return &job, nil
+}
+
+func (r *JobRepository) ClaimDueItems(ctx context.Context, limit int, lease time.Duration) ([]*models.JobItem, error) {
+	query := `UPDATE job_items i
+		SET next_attempt_at = NOW() + make_interval(secs => $2)
+		FROM jobs j
+		WHERE j.job_id = i.job_id AND i.item_id IN (
+			SELECT item_id FROM job_items
+			WHERE status = 'PENDING' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at, item_id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobItemColumns
+	return r.queryItems(ctx, query, limit, lease.Seconds())
+}
+
+func (r *JobRepository) SaveItemAttempt(ctx context.Context, item *models.JobItem) error {
+	query := `UPDATE job_items
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5, replaced_by = $6, updated_at = NOW()
+		WHERE item_id = $1
+		RETURNING updated_at`
+	err := bind(ctx, r.db).QueryRow(query, item.ItemID, item.Status, item.Attempts, item.NextAttemptAt, item.LastError,
+		item.ReplacedBy).Scan(&item.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return repository.ErrJobNotFound
+	}
+	return err
+}
+
+func (r *JobRepository) FinishJob(ctx context.Context, jobID int64) (bool, error) {
+	query := `UPDATE jobs SET status = 'COMPLETED', finished_at = NOW()
+		WHERE job_id = $1 AND status = 'RUNNING'
+			AND NOT EXISTS (SELECT 1 FROM job_items WHERE job_id = $1 AND status = 'PENDING')`
+	result, err := bind(ctx, r.db).Exec(query, jobID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func (r *JobRepository) RequeueFailedItems(ctx context.Context, jobID int64) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE job_items
+		SET status = 'PENDING', attempts = 0, next_attempt_at = NOW(), last_error = '', updated_at = NOW()
+		WHERE job_id = $1 AND status = 'FAILED'`, jobID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return repository.ErrJobNotFound
+	}
+
+	_, err = tx.Exec(`UPDATE jobs SET status = 'RUNNING', finished_at = NULL WHERE job_id = $1`, jobID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *JobRepository) queryItems(ctx context.Context, query string, args ...interface{}) ([]*models.JobItem, error) {
+	rows, err := bind(ctx, r.db).Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*models.JobItem, 0)
+	for rows.Next() {
+		item, err := scanJobItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
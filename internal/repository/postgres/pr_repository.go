@@ -0,0 +1,391 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+type PullRequestRepository struct {
+	db conn
+}
+
+func NewPullRequestRepository(db *sql.DB, queryTimeout time.Duration) *PullRequestRepository {
+	return &PullRequestRepository{db: conn{db: db, timeout: queryTimeout}}
+}
+
+func (r *PullRequestRepository) Create(ctx context.Context, pr *models.PullRequest) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id = $1)", pr.PullRequestID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return repository.ErrPRExists
+	}
+
+	changedFiles := pr.ChangedFiles
+	if changedFiles == nil {
+		changedFiles = []string{}
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(
+		`INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, changed_files)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status, now, pq.Array(changedFiles))
+	if err != nil {
+		return err
+	}
+
+	for _, reviewerID := range pr.AssignedReviewers {
+		_, err = tx.Exec(
+			`INSERT INTO pr_reviewers (pull_request_id, user_id, is_fallback) VALUES ($1, $2, $3)`,
+			pr.PullRequestID, reviewerID, containsString(pr.FallbackReviewers, reviewerID))
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *PullRequestRepository) GetByID(ctx context.Context, prID string) (*models.PullRequest, error) {
+	var pr models.PullRequest
+	var createdAt, mergedAt, closedAt sql.NullTime
+	var changedFiles pq.StringArray
+
+	query := `SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, closed_at, changed_files
+		FROM pull_requests WHERE pull_request_id = $1`
+	err := bind(ctx, r.db).QueryRow(query, prID).Scan(
+		&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &createdAt, &mergedAt, &closedAt, &changedFiles)
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrPRNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if createdAt.Valid {
+		pr.CreatedAt = &createdAt.Time
+	}
+	if mergedAt.Valid {
+		pr.MergedAt = &mergedAt.Time
+	}
+	if closedAt.Valid {
+		pr.ClosedAt = &closedAt.Time
+	}
+	if len(changedFiles) > 0 {
+		pr.ChangedFiles = []string(changedFiles)
+	}
+
+	err = r.loadReviewers(ctx, &pr)
+	if err != nil {
+		return nil, err
+	}
+
+	pr.Reviews, err = r.getReviews(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+func (r *PullRequestRepository) loadReviewers(ctx context.Context, pr *models.PullRequest) error {
+	reviewersQuery := `SELECT user_id, is_fallback FROM pr_reviewers WHERE pull_request_id = $1`
+	rows, err := bind(ctx, r.db).Query(reviewersQuery, pr.PullRequestID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var reviewerID string
+		var fallback bool
+		if err := rows.Scan(&reviewerID, &fallback); err != nil {
+			return err
+		}
+		pr.AssignedReviewers = append(pr.AssignedReviewers, reviewerID)
+		if fallback {
+			pr.FallbackReviewers = append(pr.FallbackReviewers, reviewerID)
+		}
+	}
+	return rows.Err()
+}
+
+// getReviews returns verdicts of the reviewers that are currently assigned to the PR.
+func (r *PullRequestRepository) getReviews(ctx context.Context, prID string) ([]models.Review, error) {
+	query := `SELECT rv.user_id, rv.verdict, rv.submitted_at
+		FROM pr_reviews rv
+		INNER JOIN pr_reviewers pr ON pr.pull_request_id = rv.pull_request_id AND pr.user_id = rv.user_id
+		WHERE rv.pull_request_id = $1
+		ORDER BY rv.submitted_at`
+	rows, err := bind(ctx, r.db).Query(query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []models.Review
+	for rows.Next() {
+		var review models.Review
+		if err := rows.Scan(&review.UserID, &review.Verdict, &review.SubmittedAt); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, rows.Err()
+}
+
+func (r *PullRequestRepository) SubmitReview(ctx context.Context, prID string, review *models.Review) error {
+	query := `INSERT INTO pr_reviews (pull_request_id, user_id, verdict, submitted_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (pull_request_id, user_id) DO UPDATE SET
+			verdict = EXCLUDED.verdict,
+			submitted_at = EXCLUDED.submitted_at`
+	_, err := bind(ctx, r.db).Exec(query, prID, review.UserID, review.Verdict, review.SubmittedAt)
+	return err
+}
+
+func (r *PullRequestRepository) Merge(ctx context.Context, prID string) error {
+	query := `UPDATE pull_requests 
+		SET status = 'MERGED', merged_at = COALESCE(merged_at, NOW())
+		WHERE pull_request_id = $1`
+	result, err := bind(ctx, r.db).Exec(query, prID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return repository.ErrPRNotFound
+	}
+	return nil
+}
+
+func (r *PullRequestRepository) Close(ctx context.Context, prID string) error {
+	query := `UPDATE pull_requests 
+		SET status = 'CLOSED', closed_at = COALESCE(closed_at, NOW())
+		WHERE pull_request_id = $1`
+	result, err := bind(ctx, r.db).Exec(query, prID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return repository.ErrPRNotFound
+	}
+	return nil
+}
+
+// MarkOpen moves a draft or closed PR to OPEN and adds the given reviewers.
+func (r *PullRequestRepository) MarkOpen(ctx context.Context, prID string, reviewers, fallbackReviewers []string) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`UPDATE pull_requests SET status = 'OPEN', closed_at = NULL WHERE pull_request_id = $1`,
+		prID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return repository.ErrPRNotFound
+	}
+
+	for _, reviewerID := range reviewers {
+		_, err = tx.Exec(
+			`INSERT INTO pr_reviewers (pull_request_id, user_id, is_fallback) VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING`,
+			prID, reviewerID, containsString(fallbackReviewers, reviewerID))
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *PullRequestRepository) ReassignReviewer(ctx context.Context, prID, oldUserID, newUserID string, fallback bool) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRow("SELECT status FROM pull_requests WHERE pull_request_id = $1", prID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return repository.ErrPRNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	var exists bool
+	err = tx.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM pr_reviewers WHERE pull_request_id = $1 AND user_id = $2)",
+		prID, oldUserID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return repository.ErrNotAssigned
+	}
+
+	_, err = tx.Exec(
+		`UPDATE pr_reviewers SET user_id = $1, is_fallback = $2 WHERE pull_request_id = $3 AND user_id = $4`,
+		newUserID, fallback, prID, oldUserID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PullRequestRepository) GetPRsByReviewer(ctx context.Context, userID string) ([]*models.PullRequestShort, error) {
+	query := `
+		SELECT p.pull_request_id, p.pull_request_name, p.author_id, p.status
+		FROM pull_requests p
+		INNER JOIN pr_reviewers pr ON p.pull_request_id = pr.pull_request_id
+		WHERE pr.user_id = $1
+		ORDER BY p.created_at DESC`
+	
+	rows, err := bind(ctx, r.db).Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prs []*models.PullRequestShort
+	for rows.Next() {
+		var pr models.PullRequestShort
+		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status); err != nil {
+			return nil, err
+		}
+		prs = append(prs, &pr)
+	}
+	return prs, rows.Err()
+}
+
+func (r *PullRequestRepository) GetOpenPRsWithReviewer(ctx context.Context, userID string) ([]*models.PullRequest, error) {
+	query := `
+		SELECT p.pull_request_id, p.pull_request_name, p.author_id, p.status, p.created_at, p.merged_at, p.closed_at
+		FROM pull_requests p
+		INNER JOIN pr_reviewers pr ON p.pull_request_id = pr.pull_request_id
+		WHERE pr.user_id = $1 AND p.status = 'OPEN'`
+	
+	rows, err := bind(ctx, r.db).Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prs []*models.PullRequest
+	for rows.Next() {
+		var pr models.PullRequest
+		var createdAt, mergedAt, closedAt sql.NullTime
+		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &createdAt, &mergedAt, &closedAt); err != nil {
+			return nil, err
+		}
+		if createdAt.Valid {
+			pr.CreatedAt = &createdAt.Time
+		}
+		if mergedAt.Valid {
+			pr.MergedAt = &mergedAt.Time
+		}
+		if closedAt.Valid {
+			pr.ClosedAt = &closedAt.Time
+		}
+		prs = append(prs, &pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	// Reviewers are loaded once the rows are closed: a transaction has a single
+	// connection and cannot run a query while another one is being read.
+	for _, pr := range prs {
+		if err := r.loadReviewers(ctx, pr); err != nil {
+			return nil, err
+		}
+	}
+	return prs, nil
+}
+
+
+func (r *PullRequestRepository) GetMergedPRs(ctx context.Context, from, to time.Time) ([]*models.PullRequest, error) {
+	query := `
+		SELECT p.pull_request_id, p.pull_request_name, p.author_id, p.status, p.created_at, p.merged_at,
+			COALESCE(array_agg(r.user_id) FILTER (WHERE r.user_id IS NOT NULL), '{}'),
+			COALESCE(array_agg(r.user_id) FILTER (WHERE r.is_fallback), '{}')
+		FROM pull_requests p
+		LEFT JOIN pr_reviewers r ON r.pull_request_id = p.pull_request_id
+		WHERE p.status = 'MERGED' AND p.merged_at >= $1 AND p.merged_at < $2
+		GROUP BY p.pull_request_id
+		ORDER BY p.merged_at`
+
+	rows, err := bind(ctx, r.db).Query(query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prs []*models.PullRequest
+	for rows.Next() {
+		var pr models.PullRequest
+		var createdAt, mergedAt sql.NullTime
+		var reviewers, fallbackReviewers pq.StringArray
+		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &createdAt, &mergedAt,
+			&reviewers, &fallbackReviewers); err != nil {
+			return nil, err
+		}
+		if createdAt.Valid {
+			pr.CreatedAt = &createdAt.Time
+		}
+		if mergedAt.Valid {
+			pr.MergedAt = &mergedAt.Time
+		}
+		if len(reviewers) > 0 {
+			pr.AssignedReviewers = []string(reviewers)
+		}
+		if len(fallbackReviewers) > 0 {
+			pr.FallbackReviewers = []string(fallbackReviewers)
+		}
+		prs = append(prs, &pr)
+	}
+	return prs, rows.Err()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
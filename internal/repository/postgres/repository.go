@@ -0,0 +1,195 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+	"time"
+
+	"pr-reviewer-service/internal/repository"
+)
+
+var (
+	_ repository.UserRepository         = (*UserRepository)(nil)
+	_ repository.TeamRepository         = (*TeamRepository)(nil)
+	_ repository.PullRequestRepository  = (*PullRequestRepository)(nil)
+	_ repository.AvailabilityRepository = (*AvailabilityRepository)(nil)
+	_ repository.EventRepository        = (*EventRepository)(nil)
+	_ repository.WebhookRepository      = (*WebhookRepository)(nil)
+	_ repository.UserMappingRepository  = (*UserMappingRepository)(nil)
+	_ repository.ReviewerSyncRepository = (*ReviewerSyncRepository)(nil)
+	_ repository.JobRepository          = (*JobRepository)(nil)
+	_ repository.Transactor             = (*Transactor)(nil)
+	_ repository.StatusRepository       = (*StatusRepository)(nil)
+)
+
+// dbtx is implemented by both *sql.DB and *sql.Tx, so the same repository
+// code runs on the pool or inside a transaction.
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// conn is the pool or a transaction together with the deadline given to every
+// statement run on it. With a zero timeout statements are bounded only by the
+// context of the call.
+type conn struct {
+	db      dbtx
+	timeout time.Duration
+}
+
+// bind returns the statements of c bound to ctx. Every statement gets its own
+// deadline, and one that runs out of it fails with repository.ErrTimeout.
+// Failed statements are logged together with the attributes carried by ctx.
+func bind(ctx context.Context, c conn) *boundDB {
+	return &boundDB{ctx: ctx, conn: c}
+}
+
+type boundDB struct {
+	ctx  context.Context
+	conn conn
+}
+
+func (b *boundDB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	stmt := b.start(query)
+	defer stmt.cancel()
+
+	result, err := b.conn.db.ExecContext(stmt.ctx, query, args...)
+	return result, stmt.check(err)
+}
+
+func (b *boundDB) Query(query string, args ...interface{}) (*rows, error) {
+	stmt := b.start(query)
+	r, err := b.conn.db.QueryContext(stmt.ctx, query, args...)
+	if err != nil {
+		err = stmt.check(err)
+		stmt.cancel()
+		return nil, err
+	}
+	return &rows{Rows: r, stmt: stmt}, nil
+}
+
+func (b *boundDB) QueryRow(query string, args ...interface{}) *row {
+	stmt := b.start(query)
+	return &row{Row: b.conn.db.QueryRowContext(stmt.ctx, query, args...), stmt: stmt}
+}
+
+func (b *boundDB) start(query string) *statement {
+	stmt := &statement{query: query}
+	if b.conn.timeout > 0 {
+		stmt.ctx, stmt.cancel = context.WithTimeout(b.ctx, b.conn.timeout)
+	} else {
+		stmt.ctx, stmt.cancel = context.WithCancel(b.ctx)
+	}
+	return stmt
+}
+
+// statement is a single statement in flight. Its deadline is released once
+// the result is read.
+type statement struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	query  string
+}
+
+// check logs err and reports it as repository.ErrTimeout when the statement
+// ran out of time. sql.ErrNoRows is an expected outcome and passes unchanged.
+func (s *statement) check(err error) error {
+	if err == nil || err == sql.ErrNoRows {
+		return err
+	}
+	query := strings.Join(strings.Fields(s.query), " ")
+	if len(query) > 120 {
+		query = query[:120] + "..."
+	}
+	timedOut := s.ctx.Err() == context.DeadlineExceeded
+	slog.ErrorContext(s.ctx, "database statement failed", "statement", query, "timed_out", timedOut, "error", err)
+
+	if timedOut {
+		return repository.ErrTimeout
+	}
+	return err
+}
+
+// rows releases the deadline of its statement when closed.
+type rows struct {
+	*sql.Rows
+	stmt *statement
+}
+
+func (r *rows) Scan(dest ...interface{}) error {
+	return r.stmt.check(r.Rows.Scan(dest...))
+}
+
+func (r *rows) Err() error {
+	return r.stmt.check(r.Rows.Err())
+}
+
+func (r *rows) Close() error {
+	defer r.stmt.cancel()
+	return r.Rows.Close()
+}
+
+// row releases the deadline of its statement when scanned.
+type row struct {
+	*sql.Row
+	stmt *statement
+}
+
+func (r *row) Scan(dest ...interface{}) error {
+	defer r.stmt.cancel()
+	return r.stmt.check(r.Row.Scan(dest...))
+}
+
+// localTx groups the statements of a single repository method. On the pool it
+// is a transaction of its own; inside an outer transaction the statements join
+// it and committing or rolling back is left to the owner of that transaction.
+// Statements get deadlines and are logged like those of bind.
+type localTx struct {
+	*sql.Tx
+	stmts *boundDB
+	owned bool
+}
+
+func begin(ctx context.Context, c conn) (*localTx, error) {
+	if tx, ok := c.db.(*sql.Tx); ok {
+		return &localTx{Tx: tx, stmts: bind(ctx, c)}, nil
+	}
+	tx, err := c.db.(*sql.DB).BeginTx(ctx, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, "database transaction failed to begin", "error", err)
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, repository.ErrTimeout
+		}
+		return nil, err
+	}
+	return &localTx{Tx: tx, stmts: bind(ctx, conn{db: tx, timeout: c.timeout}), owned: true}, nil
+}
+
+func (t *localTx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return t.stmts.Exec(query, args...)
+}
+
+func (t *localTx) Query(query string, args ...interface{}) (*rows, error) {
+	return t.stmts.Query(query, args...)
+}
+
+func (t *localTx) QueryRow(query string, args ...interface{}) *row {
+	return t.stmts.QueryRow(query, args...)
+}
+
+func (t *localTx) Commit() error {
+	if !t.owned {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+func (t *localTx) Rollback() error {
+	if !t.owned {
+		return nil
+	}
+	return t.Tx.Rollback()
+}
@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"pr-reviewer-service/internal/repository"
+)
+
+func init() {
+	sql.Register("slow", slowDriver{})
+}
+
+// slowDriver opens connections whose statements take the duration given as
+// the data source name. A statement whose context is done first fails with
+// the context error, the way lib/pq reports a canceled query.
+type slowDriver struct{}
+
+func (slowDriver) Open(name string) (driver.Conn, error) {
+	delay, err := time.ParseDuration(name)
+	if err != nil {
+		return nil, err
+	}
+	return slowConn{delay: delay}, nil
+}
+
+type slowConn struct {
+	delay time.Duration
+}
+
+func (c slowConn) wait(ctx context.Context) error {
+	timer := time.NewTimer(c.delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c slowConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c slowConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c slowConn) Begin() (driver.Tx, error) { return slowTx{}, nil }
+func (c slowConn) Close() error              { return nil }
+
+type slowTx struct{}
+
+func (slowTx) Commit() error   { return nil }
+func (slowTx) Rollback() error { return nil }
+
+func openSlow(t *testing.T, delay time.Duration) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("slow", delay.String())
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// TestQueryTimeout checks the per-statement deadline set by DB_QUERY_TIMEOUT.
+func TestQueryTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		delay   time.Duration
+		timeout time.Duration
+		wantErr error
+	}{
+		{name: "within the timeout", delay: 10 * time.Millisecond, timeout: time.Second},
+		{name: "past the timeout", delay: time.Hour, timeout: 20 * time.Millisecond, wantErr: repository.ErrTimeout},
+		{name: "no timeout", delay: 50 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openSlow(t, tt.delay)
+			ctx := context.Background()
+
+			err := NewUserRepository(db, tt.timeout).SetIsActive(ctx, "bob", false)
+			if err != tt.wantErr {
+				t.Errorf("SetIsActive error = %v, want %v", err, tt.wantErr)
+			}
+
+			// Statements of a transaction get the same deadline each.
+			err = NewTransactor(db, tt.timeout).Atomic(ctx, func(repos *repository.Repositories) error {
+				return repos.Users.SetIsActive(ctx, "bob", false)
+			})
+			if err != tt.wantErr {
+				t.Errorf("SetIsActive in a transaction error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQueryTimeoutIsPerStatement(t *testing.T) {
+	db := openSlow(t, 30*time.Millisecond)
+	repo := NewUserRepository(db, 50*time.Millisecond)
+	ctx := context.Background()
+
+	// Together the statements outlast the timeout, but none of them does.
+	for i := 0; i < 3; i++ {
+		if err := repo.SetIsActive(ctx, "bob", false); err != nil {
+			t.Fatalf("statement %d: %v", i, err)
+		}
+	}
+}
+
+func TestCanceledQueryIsNotTimeout(t *testing.T) {
+	db := openSlow(t, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	err := NewUserRepository(db, time.Hour).SetIsActive(ctx, "bob", false)
+	if err != context.Canceled {
+		t.Errorf("SetIsActive error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+type ReviewerSyncRepository struct {
+	db conn
+}
+
+func NewReviewerSyncRepository(db *sql.DB, queryTimeout time.Duration) *ReviewerSyncRepository {
+	return &ReviewerSyncRepository{db: conn{db: db, timeout: queryTimeout}}
+}
+
+const reviewerSyncColumns = `sync_id, provider, pull_request_id, project_id, merge_request_iid, reviewers, status, error, created_at, pushed_at`
+
+func scanReviewerSync(row rowScanner) (*models.ReviewerSync, error) {
+	var s models.ReviewerSync
+	var reviewers pq.StringArray
+	var pushedAt sql.NullTime
+	err := row.Scan(&s.SyncID, &s.Provider, &s.PullRequestID, &s.ProjectID, &s.MergeRequestIID,
+		&reviewers, &s.Status, &s.Error, &s.CreatedAt, &pushedAt)
+	if err != nil {
+		return nil, err
+	}
+	s.Reviewers = []string(reviewers)
+	if pushedAt.Valid {
+		s.PushedAt = &pushedAt.Time
+	}
+	return &s, nil
+}
+
+func (r *ReviewerSyncRepository) Create(ctx context.Context, s *models.ReviewerSync) error {
+	query := `INSERT INTO reviewer_syncs (provider, pull_request_id, project_id, merge_request_iid, reviewers, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING sync_id, created_at`
+	return bind(ctx, r.db).QueryRow(query, s.Provider, s.PullRequestID, s.ProjectID, s.MergeRequestIID,
+		pq.Array(s.Reviewers), s.Status).Scan(&s.SyncID, &s.CreatedAt)
+}
+
+func (r *ReviewerSyncRepository) GetByID(ctx context.Context, syncID int64) (*models.ReviewerSync, error) {
+	query := `SELECT ` + reviewerSyncColumns + ` FROM reviewer_syncs WHERE sync_id = $1`
+	s, err := scanReviewerSync(bind(ctx, r.db).QueryRow(query, syncID))
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrSyncNotFound
+	}
+	return s, err
+}
+
+func (r *ReviewerSyncRepository) GetByPullRequest(ctx context.Context, prID string) ([]*models.ReviewerSync, error) {
+	query := `SELECT ` + reviewerSyncColumns + `
+		FROM reviewer_syncs
+		WHERE pull_request_id = $1
+		ORDER BY sync_id`
+	rows, err := bind(ctx, r.db).Query(query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	syncs := make([]*models.ReviewerSync, 0)
+	for rows.Next() {
+		s, err := scanReviewerSync(rows)
+		if err != nil {
+			return nil, err
+		}
+		syncs = append(syncs, s)
+	}
+	return syncs, rows.Err()
+}
+
+func (r *ReviewerSyncRepository) UpdateStatus(ctx context.Context, s *models.ReviewerSync) error {
+	query := `UPDATE reviewer_syncs SET status = $2, error = $3, pushed_at = $4 WHERE sync_id = $1`
+	result, err := bind(ctx, r.db).Exec(query, s.SyncID, s.Status, s.Error, s.PushedAt)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return repository.ErrSyncNotFound
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+)
+
+type StatusRepository struct {
+	db *sql.DB
+}
+
+func NewStatusRepository(db *sql.DB) *StatusRepository {
+	return &StatusRepository{db: db}
+}
+
+func (r *StatusRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+// SchemaVersion reads the table maintained by golang-migrate. Without any
+// applied migration the version is 0.
+func (r *StatusRepository) SchemaVersion(ctx context.Context) (int64, bool, error) {
+	var version int64
+	var dirty bool
+	err := r.db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return version, dirty, nil
+}
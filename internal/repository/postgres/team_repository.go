@@ -0,0 +1,212 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+type TeamRepository struct {
+	db       conn
+	userRepo *UserRepository
+}
+
+func NewTeamRepository(db *sql.DB, userRepo *UserRepository, queryTimeout time.Duration) *TeamRepository {
+	return &TeamRepository{
+		db:       conn{db: db, timeout: queryTimeout},
+		userRepo: userRepo,
+	}
+}
+
+func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)", team.TeamName).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return repository.ErrTeamExists
+	}
+
+	_, err = tx.Exec("INSERT INTO teams (team_name) VALUES ($1)", team.TeamName)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range team.Members {
+		if member.UserID == "" {
+			continue
+		}
+		user := &models.User{
+			UserID:         member.UserID,
+			Username:       member.Username,
+			TeamName:       team.TeamName,
+			IsActive:       member.IsActive,
+			MaxOpenReviews: member.MaxOpenReviews,
+		}
+		query := `INSERT INTO users (user_id, username, team_name, is_active, max_open_reviews)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (user_id) DO UPDATE SET
+				username = EXCLUDED.username,
+				team_name = EXCLUDED.team_name,
+				is_active = EXCLUDED.is_active,
+				max_open_reviews = EXCLUDED.max_open_reviews`
+		_, err = tx.Exec(query, user.UserID, user.Username, user.TeamName, user.IsActive, user.MaxOpenReviews)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *TeamRepository) GetByName(ctx context.Context, teamName string) (*models.Team, error) {
+	var exists bool
+	err := bind(ctx, r.db).QueryRow("SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)", teamName).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, repository.ErrTeamNotFound
+	}
+
+	users, err := r.userRepo.GetUsersByTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]models.TeamMember, len(users))
+	for i, user := range users {
+		members[i] = models.TeamMember{
+			UserID:         user.UserID,
+			Username:       user.Username,
+			IsActive:       user.IsActive,
+			MaxOpenReviews: user.MaxOpenReviews,
+		}
+	}
+
+	return &models.Team{
+		TeamName: teamName,
+		Members:  members,
+	}, nil
+}
+
+
+func (r *TeamRepository) GetSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	var exists bool
+	err := bind(ctx, r.db).QueryRow("SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)", teamName).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, repository.ErrTeamNotFound
+	}
+
+	settings := models.DefaultTeamSettings(teamName)
+	var fallbackTeams pq.StringArray
+	query := `SELECT reviewer_count, min_reviewers, allow_self_review, capacity_policy, required_approvals,
+			fallback_teams
+		FROM team_settings WHERE team_name = $1`
+	err = bind(ctx, r.db).QueryRow(query, teamName).Scan(
+		&settings.ReviewerCount, &settings.MinReviewers, &settings.AllowSelfReview, &settings.CapacityPolicy,
+		&settings.RequiredApprovals, &fallbackTeams)
+	if err == sql.ErrNoRows {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(fallbackTeams) > 0 {
+		settings.FallbackTeams = []string(fallbackTeams)
+	}
+	return settings, nil
+}
+
+func (r *TeamRepository) UpdateSettings(ctx context.Context, settings *models.TeamSettings) error {
+	var exists bool
+	err := bind(ctx, r.db).QueryRow("SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)", settings.TeamName).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return repository.ErrTeamNotFound
+	}
+
+	fallbackTeams := settings.FallbackTeams
+	if fallbackTeams == nil {
+		fallbackTeams = []string{}
+	}
+
+	query := `INSERT INTO team_settings (team_name, reviewer_count, min_reviewers, allow_self_review, capacity_policy,
+			required_approvals, fallback_teams)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (team_name) DO UPDATE SET
+			reviewer_count = EXCLUDED.reviewer_count,
+			min_reviewers = EXCLUDED.min_reviewers,
+			allow_self_review = EXCLUDED.allow_self_review,
+			capacity_policy = EXCLUDED.capacity_policy,
+			required_approvals = EXCLUDED.required_approvals,
+			fallback_teams = EXCLUDED.fallback_teams`
+	_, err = bind(ctx, r.db).Exec(query, settings.TeamName, settings.ReviewerCount, settings.MinReviewers,
+		settings.AllowSelfReview, settings.CapacityPolicy, settings.RequiredApprovals, pq.Array(fallbackTeams))
+	return err
+}
+
+func (r *TeamRepository) GetCodeOwners(ctx context.Context, teamName string) (*models.TeamCodeOwners, error) {
+	var exists bool
+	err := bind(ctx, r.db).QueryRow("SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)", teamName).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, repository.ErrTeamNotFound
+	}
+
+	codeOwners := &models.TeamCodeOwners{TeamName: teamName}
+	var updatedAt time.Time
+	err = bind(ctx, r.db).QueryRow(`SELECT content, updated_at FROM team_codeowners WHERE team_name = $1`, teamName).
+		Scan(&codeOwners.Content, &updatedAt)
+	if err == sql.ErrNoRows {
+		return codeOwners, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	codeOwners.UpdatedAt = &updatedAt
+	return codeOwners, nil
+}
+
+func (r *TeamRepository) SetCodeOwners(ctx context.Context, codeOwners *models.TeamCodeOwners) error {
+	var exists bool
+	err := bind(ctx, r.db).QueryRow("SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)", codeOwners.TeamName).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return repository.ErrTeamNotFound
+	}
+
+	query := `INSERT INTO team_codeowners (team_name, content, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (team_name) DO UPDATE SET
+			content = EXCLUDED.content,
+			updated_at = EXCLUDED.updated_at
+		RETURNING updated_at`
+	var updatedAt time.Time
+	err = bind(ctx, r.db).QueryRow(query, codeOwners.TeamName, codeOwners.Content).Scan(&updatedAt)
+	if err != nil {
+		return err
+	}
+	codeOwners.UpdatedAt = &updatedAt
+	return nil
+}
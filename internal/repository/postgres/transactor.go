@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pr-reviewer-service/internal/repository"
+)
+
+type Transactor struct {
+	db           *sql.DB
+	queryTimeout time.Duration
+}
+
+// NewTransactor returns a transactor whose transactions give every statement
+// queryTimeout to finish. The transactions themselves are bounded only by the
+// context passed to Atomic and DryRun.
+func NewTransactor(db *sql.DB, queryTimeout time.Duration) *Transactor {
+	return &Transactor{db: db, queryTimeout: queryTimeout}
+}
+
+func (t *Transactor) Atomic(ctx context.Context, fn func(repos *repository.Repositories) error) error {
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(repositories(tx, t.queryTimeout)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (t *Transactor) DryRun(ctx context.Context, fn func(repos *repository.Repositories) error) error {
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	return fn(repositories(tx, t.queryTimeout))
+}
+
+func repositories(tx *sql.Tx, queryTimeout time.Duration) *repository.Repositories {
+	c := conn{db: tx, timeout: queryTimeout}
+	userRepo := &UserRepository{db: c}
+	return &repository.Repositories{
+		Users:        userRepo,
+		Teams:        &TeamRepository{db: c, userRepo: userRepo},
+		PullRequests: &PullRequestRepository{db: c},
+		Events:       &EventRepository{db: c},
+		Jobs:         &JobRepository{db: c},
+	}
+}
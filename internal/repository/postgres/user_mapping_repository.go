@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+type UserMappingRepository struct {
+	db conn
+}
+
+func NewUserMappingRepository(db *sql.DB, queryTimeout time.Duration) *UserMappingRepository {
+	return &UserMappingRepository{db: conn{db: db, timeout: queryTimeout}}
+}
+
+func (r *UserMappingRepository) Set(ctx context.Context, m *models.UserMapping) error {
+	query := `INSERT INTO integration_user_mappings (provider, login, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (provider, login) DO UPDATE SET user_id = EXCLUDED.user_id`
+	_, err := bind(ctx, r.db).Exec(query, m.Provider, m.Login, m.UserID)
+	return err
+}
+
+func (r *UserMappingRepository) Get(ctx context.Context, provider, login string) (*models.UserMapping, error) {
+	query := `SELECT provider, login, user_id
+		FROM integration_user_mappings
+		WHERE provider = $1 AND login = $2`
+	var m models.UserMapping
+	err := bind(ctx, r.db).QueryRow(query, provider, login).Scan(&m.Provider, &m.Login, &m.UserID)
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrMappingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *UserMappingRepository) GetByProvider(ctx context.Context, provider string) ([]*models.UserMapping, error) {
+	query := `SELECT provider, login, user_id
+		FROM integration_user_mappings
+		WHERE provider = $1
+		ORDER BY login`
+	rows, err := bind(ctx, r.db).Query(query, provider)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mappings := make([]*models.UserMapping, 0)
+	for rows.Next() {
+		var m models.UserMapping
+		if err := rows.Scan(&m.Provider, &m.Login, &m.UserID); err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, &m)
+	}
+	return mappings, rows.Err()
+}
+
+func (r *UserMappingRepository) Delete(ctx context.Context, provider, login string) error {
+	result, err := bind(ctx, r.db).Exec(`DELETE FROM integration_user_mappings WHERE provider = $1 AND login = $2`, provider, login)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return repository.ErrMappingNotFound
+	}
+	return nil
+}
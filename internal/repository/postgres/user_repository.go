@@ -0,0 +1,242 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+type UserRepository struct {
+	db conn
+}
+
+func NewUserRepository(db *sql.DB, queryTimeout time.Duration) *UserRepository {
+	return &UserRepository{db: conn{db: db, timeout: queryTimeout}}
+}
+
+const userColumns = `user_id, username, team_name, is_active, max_open_reviews`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUser(row rowScanner) (*models.User, error) {
+	var user models.User
+	var maxOpenReviews sql.NullInt64
+	if err := row.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive, &maxOpenReviews); err != nil {
+		return nil, err
+	}
+	if maxOpenReviews.Valid {
+		limit := int(maxOpenReviews.Int64)
+		user.MaxOpenReviews = &limit
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) CreateOrUpdate(ctx context.Context, user *models.User) error {
+	query := `INSERT INTO users (user_id, username, team_name, is_active, max_open_reviews)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE SET
+			username = EXCLUDED.username,
+			team_name = EXCLUDED.team_name,
+			is_active = EXCLUDED.is_active,
+			max_open_reviews = EXCLUDED.max_open_reviews`
+	_, err := bind(ctx, r.db).Exec(query, user.UserID, user.Username, user.TeamName, user.IsActive, user.MaxOpenReviews)
+	return err
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, userID string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE user_id = $1`
+	user, err := scanUser(bind(ctx, r.db).QueryRow(query, userID))
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *UserRepository) SetIsActive(ctx context.Context, userID string, isActive bool) error {
+	query := `UPDATE users SET is_active = $1 WHERE user_id = $2`
+	result, err := bind(ctx, r.db).Exec(query, isActive, userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return repository.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) GetActiveUsersByTeam(ctx context.Context, teamName, excludeUserID string) ([]*models.User, error) {
+	query := `SELECT ` + userColumns + `
+		FROM users 
+		WHERE team_name = $1 AND is_active = true AND user_id != $2
+			AND NOT EXISTS (
+				SELECT 1 FROM user_availability a
+				WHERE a.user_id = users.user_id AND a.starts_at <= NOW() AND a.ends_at > NOW()
+			)`
+	rows, err := bind(ctx, r.db).Query(query, teamName, excludeUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (r *UserRepository) GetUsersByTeam(ctx context.Context, teamName string) ([]*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE team_name = $1`
+	rows, err := bind(ctx, r.db).Query(query, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (r *UserRepository) SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) error {
+	query := `UPDATE users SET max_open_reviews = $1 WHERE user_id = $2`
+	result, err := bind(ctx, r.db).Exec(query, maxOpenReviews, userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return repository.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) BulkSetIsActive(ctx context.Context, userIDs []string, isActive bool) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	query := `UPDATE users SET is_active = $1 WHERE user_id = ANY($2::text[])`
+	_, err := bind(ctx, r.db).Exec(query, isActive, pq.Array(userIDs))
+	return err
+}
+
+func (r *UserRepository) GetReviewerCount(ctx context.Context, userID string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM pr_reviewers WHERE user_id = $1`
+	err := bind(ctx, r.db).QueryRow(query, userID).Scan(&count)
+	return count, err
+}
+
+func (r *UserRepository) GetOpenReviewCounts(ctx context.Context, userIDs []string) (map[string]int, error) {
+	counts := make(map[string]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return counts, nil
+	}
+
+	query := `SELECT pr.user_id, COUNT(*)
+		FROM pr_reviewers pr
+		INNER JOIN pull_requests p ON p.pull_request_id = pr.pull_request_id
+		WHERE pr.user_id = ANY($1::text[]) AND p.status = 'OPEN'
+		GROUP BY pr.user_id`
+	rows, err := bind(ctx, r.db).Query(query, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID string
+		var count int
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, err
+		}
+		counts[userID] = count
+	}
+	return counts, rows.Err()
+}
+
+func (r *UserRepository) GetAuthoredPRCount(ctx context.Context, userID string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM pull_requests WHERE author_id = $1`
+	err := bind(ctx, r.db).QueryRow(query, userID).Scan(&count)
+	return count, err
+}
+
+func (r *UserRepository) GetAllUsersStats(ctx context.Context, filter models.StatsFilter) ([]*models.UserStats, error) {
+	// Every count is a subquery of its own: joining reviewers and authored PRs
+	// to users at once would multiply the rows of one by the other.
+	query := `
+		SELECT
+			u.user_id,
+			u.username,
+			u.team_name,
+			u.is_active,
+			(SELECT COUNT(*) FROM pr_reviewers r
+				INNER JOIN pull_requests p ON p.pull_request_id = r.pull_request_id
+				WHERE r.user_id = u.user_id AND p.created_at >= $2 AND p.created_at < $3) as assigned_count,
+			(SELECT COUNT(*) FROM pull_requests p
+				WHERE p.author_id = u.user_id AND p.created_at >= $2 AND p.created_at < $3) as authored_count,
+			(SELECT COUNT(*) FROM pr_reviewers r
+				INNER JOIN pull_requests op ON op.pull_request_id = r.pull_request_id
+				WHERE r.user_id = u.user_id AND op.status = 'OPEN') as open_review_count,
+			u.max_open_reviews,
+			(SELECT COUNT(*) FROM pr_reviews rv
+				WHERE rv.user_id = u.user_id AND rv.submitted_at >= $2 AND rv.submitted_at < $3) as review_count,
+			(SELECT MIN(rv.submitted_at) FROM pr_reviews rv
+				WHERE rv.user_id = u.user_id AND rv.submitted_at >= $2 AND rv.submitted_at < $3) as first_review_at
+		FROM users u
+		WHERE $1::text = '' OR u.team_name = $1
+		ORDER BY u.user_id`
+
+	rows, err := bind(ctx, r.db).Query(query, filter.TeamName, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []*models.UserStats
+	for rows.Next() {
+		var s models.UserStats
+		var maxOpenReviews sql.NullInt64
+		var firstReviewAt sql.NullTime
+		if err := rows.Scan(&s.UserID, &s.Username, &s.TeamName, &s.IsActive, &s.AssignedAsReviewerCount, &s.AuthoredPRCount,
+			&s.OpenReviewCount, &maxOpenReviews, &s.ReviewCount, &firstReviewAt); err != nil {
+			return nil, err
+		}
+		if maxOpenReviews.Valid {
+			limit := int(maxOpenReviews.Int64)
+			s.MaxOpenReviews = &limit
+		}
+		if firstReviewAt.Valid {
+			s.FirstReviewAt = &firstReviewAt.Time
+		}
+		stats = append(stats, &s)
+	}
+	return stats, rows.Err()
+}
@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+type WebhookRepository struct {
+	db conn
+}
+
+func NewWebhookRepository(db *sql.DB, queryTimeout time.Duration) *WebhookRepository {
+	return &WebhookRepository{db: conn{db: db, timeout: queryTimeout}}
+}
+
+const deliveryColumns = `delivery_id, subscription_id, event_type, payload, status, attempts, next_attempt_at, last_error, created_at, delivered_at`
+
+func scanSubscription(row rowScanner) (*models.WebhookSubscription, error) {
+	var sub models.WebhookSubscription
+	var events pq.StringArray
+	if err := row.Scan(&sub.SubscriptionID, &sub.URL, &events, &sub.Secret, &sub.CreatedAt); err != nil {
+		return nil, err
+	}
+	sub.Events = []string(events)
+	return &sub, nil
+}
+
+func scanDelivery(row rowScanner) (*models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	var payload []byte
+	var deliveredAt sql.NullTime
+	err := row.Scan(&d.DeliveryID, &d.SubscriptionID, &d.EventType, &payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.LastError, &d.CreatedAt, &deliveredAt)
+	if err != nil {
+		return nil, err
+	}
+	d.Payload = payload
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	return &d, nil
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	query := `INSERT INTO webhook_subscriptions (url, events, secret)
+		VALUES ($1, $2, $3)
+		RETURNING subscription_id, created_at`
+	return bind(ctx, r.db).QueryRow(query, sub.URL, pq.Array(sub.Events), sub.Secret).Scan(&sub.SubscriptionID, &sub.CreatedAt)
+}
+
+func (r *WebhookRepository) GetSubscription(ctx context.Context, subscriptionID int64) (*models.WebhookSubscription, error) {
+	query := `SELECT subscription_id, url, events, secret, created_at
+		FROM webhook_subscriptions
+		WHERE subscription_id = $1`
+	sub, err := scanSubscription(bind(ctx, r.db).QueryRow(query, subscriptionID))
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrSubscriptionNotFound
+	}
+	return sub, err
+}
+
+func (r *WebhookRepository) GetSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	rows, err := bind(ctx, r.db).Query(`SELECT subscription_id, url, events, secret, created_at
+		FROM webhook_subscriptions
+		ORDER BY subscription_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := make([]*models.WebhookSubscription, 0)
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, subscriptionID int64) error {
+	result, err := bind(ctx, r.db).Exec(`DELETE FROM webhook_subscriptions WHERE subscription_id = $1`, subscriptionID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return repository.ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+func (r *WebhookRepository) EnqueueDelivery(ctx context.Context, d *models.WebhookDelivery) error {
+	query := `INSERT INTO webhook_deliveries (subscription_id, event_type, payload)
+		VALUES ($1, $2, $3)
+		RETURNING ` + deliveryColumns
+	saved, err := scanDelivery(bind(ctx, r.db).QueryRow(query, d.SubscriptionID, d.EventType, []byte(d.Payload)))
+	if err != nil {
+		return err
+	}
+	*d = *saved
+	return nil
+}
+
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error) {
+	query := `UPDATE webhook_deliveries
+		SET next_attempt_at = NOW() + make_interval(secs => $2)
+		WHERE delivery_id IN (
+			SELECT delivery_id FROM webhook_deliveries
+			WHERE status = 'PENDING' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns
+	return r.queryDeliveries(ctx, query, limit, lease.Seconds())
+}
+
+func (r *WebhookRepository) SaveDeliveryAttempt(ctx context.Context, d *models.WebhookDelivery) error {
+	query := `UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5, delivered_at = $6
+		WHERE delivery_id = $1`
+	result, err := bind(ctx, r.db).Exec(query, d.DeliveryID, d.Status, d.Attempts, d.NextAttemptAt, d.LastError, d.DeliveredAt)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return repository.ErrDeliveryNotFound
+	}
+	return nil
+}
+
+func (r *WebhookRepository) GetDeadDeliveries(ctx context.Context) ([]*models.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries
+		WHERE status = 'DEAD'
+		ORDER BY delivery_id`
+	return r.queryDeliveries(ctx, query)
+}
+
+func (r *WebhookRepository) RequeueDelivery(ctx context.Context, deliveryID int64) (*models.WebhookDelivery, error) {
+	query := `UPDATE webhook_deliveries
+		SET status = 'PENDING', attempts = 0, next_attempt_at = NOW(), last_error = ''
+		WHERE delivery_id = $1 AND status = 'DEAD'
+		RETURNING ` + deliveryColumns
+	d, err := scanDelivery(bind(ctx, r.db).QueryRow(query, deliveryID))
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrDeliveryNotFound
+	}
+	return d, err
+}
+
+func (r *WebhookRepository) queryDeliveries(ctx context.Context, query string, args ...interface{}) ([]*models.WebhookDelivery, error) {
+	rows, err := bind(ctx, r.db).Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]*models.WebhookDelivery, 0)
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrTeamExists   = errors.New("team already exists")
+	ErrTeamNotFound = errors.New("team not found")
+	ErrPRExists     = errors.New("PR already exists")
+	ErrPRNotFound   = errors.New("PR not found")
+	ErrNotAssigned  = errors.New("reviewer is not assigned")
+
+	ErrAvailabilityNotFound = errors.New("availability window not found")
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrMappingNotFound      = errors.New("user mapping not found")
+	ErrSyncNotFound         = errors.New("reviewer sync not found")
+	ErrJobNotFound          = errors.New("job not found")
+
+	// ErrTimeout is returned when a query does not finish before its
+	// deadline.
+	ErrTimeout = errors.New("database query timed out")
+)
+
+type UserRepository interface {
+	CreateOrUpdate(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, userID string) (*models.User, error)
+	SetIsActive(ctx context.Context, userID string, isActive bool) error
+	SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) error
+	GetActiveUsersByTeam(ctx context.Context, teamName, excludeUserID string) ([]*models.User, error)
+	GetUsersByTeam(ctx context.Context, teamName string) ([]*models.User, error)
+	BulkSetIsActive(ctx context.Context, userIDs []string, isActive bool) error
+	GetReviewerCount(ctx context.Context, userID string) (int, error)
+	GetOpenReviewCounts(ctx context.Context, userIDs []string) (map[string]int, error)
+	GetAuthoredPRCount(ctx context.Context, userID string) (int, error)
+	// GetAllUsersStats returns the stats of the users matching filter, except
+	// the review latency which is computed from GetMergedPRs.
+	GetAllUsersStats(ctx context.Context, filter models.StatsFilter) ([]*models.UserStats, error)
+}
+
+type TeamRepository interface {
+	Create(ctx context.Context, team *models.Team) error
+	GetByName(ctx context.Context, teamName string) (*models.Team, error)
+	GetSettings(ctx context.Context, teamName string) (*models.TeamSettings, error)
+	UpdateSettings(ctx context.Context, settings *models.TeamSettings) error
+	GetCodeOwners(ctx context.Context, teamName string) (*models.TeamCodeOwners, error)
+	SetCodeOwners(ctx context.Context, codeOwners *models.TeamCodeOwners) error
+}
+
+type PullRequestRepository interface {
+	Create(ctx context.Context, pr *models.PullRequest) error
+	GetByID(ctx context.Context, prID string) (*models.PullRequest, error)
+	Merge(ctx context.Context, prID string) error
+	Close(ctx context.Context, prID string) error
+	// MarkOpen moves the PR to OPEN and adds reviewers; those also listed in
+	// fallbackReviewers are flagged as coming from a fallback team.
+	MarkOpen(ctx context.Context, prID string, reviewers, fallbackReviewers []string) error
+	SubmitReview(ctx context.Context, prID string, review *models.Review) error
+	ReassignReviewer(ctx context.Context, prID, oldUserID, newUserID string, fallback bool) error
+	GetPRsByReviewer(ctx context.Context, userID string) ([]*models.PullRequestShort, error)
+	GetOpenPRsWithReviewer(ctx context.Context, userID string) ([]*models.PullRequest, error)
+	// GetMergedPRs returns PRs merged within [from, to) with their reviewers,
+	// without changed files and reviews.
+	GetMergedPRs(ctx context.Context, from, to time.Time) ([]*models.PullRequest, error)
+}
+
+type AvailabilityRepository interface {
+	Create(ctx context.Context, a *models.Availability) error
+	GetByUser(ctx context.Context, userID string) ([]*models.Availability, error)
+	Delete(ctx context.Context, availabilityID int64) error
+	GetStartedPendingReassignment(ctx context.Context) ([]*models.Availability, error)
+	MarkReviewsReassigned(ctx context.Context, availabilityID int64) error
+}
+
+type EventRepository interface {
+	Append(ctx context.Context, event *models.Event) error
+	GetByPullRequest(ctx context.Context, prID string) ([]*models.Event, error)
+	GetByUser(ctx context.Context, userID string) ([]*models.Event, error)
+}
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error
+	GetSubscription(ctx context.Context, subscriptionID int64) (*models.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionID int64) error
+	EnqueueDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	// ClaimDueDeliveries returns pending deliveries that are due and moves their
+	// next attempt forward by lease, so concurrent workers do not pick them up.
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error)
+	SaveDeliveryAttempt(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDeadDeliveries(ctx context.Context) ([]*models.WebhookDelivery, error)
+	// RequeueDelivery moves a dead delivery back to the queue with a fresh
+	// attempt counter. ErrDeliveryNotFound is returned for non-dead deliveries.
+	RequeueDelivery(ctx context.Context, deliveryID int64) (*models.WebhookDelivery, error)
+}
+
+type UserMappingRepository interface {
+	Set(ctx context.Context, mapping *models.UserMapping) error
+	Get(ctx context.Context, provider, login string) (*models.UserMapping, error)
+	GetByProvider(ctx context.Context, provider string) ([]*models.UserMapping, error)
+	Delete(ctx context.Context, provider, login string) error
+}
+
+type ReviewerSyncRepository interface {
+	Create(ctx context.Context, sync *models.ReviewerSync) error
+	GetByID(ctx context.Context, syncID int64) (*models.ReviewerSync, error)
+	GetByPullRequest(ctx context.Context, prID string) ([]*models.ReviewerSync, error)
+	UpdateStatus(ctx context.Context, sync *models.ReviewerSync) error
+}
+
+type JobRepository interface {
+	// CreateJob stores the job with its items. A job without items is stored
+	// as COMPLETED.
+	CreateJob(ctx context.Context, job *models.Job) error
+	GetJob(ctx context.Context, jobID int64) (*models.Job, error)
+	// ClaimDueItems returns pending items that are due and moves their next
+	// attempt forward by lease, so concurrent workers do not pick them up.
+	ClaimDueItems(ctx context.Context, limit int, lease time.Duration) ([]*models.JobItem, error)
+	SaveItemAttempt(ctx context.Context, item *models.JobItem) error
+	// FinishJob marks a running job COMPLETED once none of its items is
+	// pending and reports whether this call did so.
+	FinishJob(ctx context.Context, jobID int64) (bool, error)
+	// RequeueFailedItems returns the FAILED items of the job to PENDING with a
+	// fresh attempt counter and reopens the job. ErrJobNotFound is returned
+	// when the job has no FAILED items.
+	RequeueFailedItems(ctx context.Context, jobID int64) error
+}
+
+// Repositories are the repositories bound to a single transaction.
+type Repositories struct {
+	Users        UserRepository
+	Teams        TeamRepository
+	PullRequests PullRequestRepository
+	Events       EventRepository
+	Jobs         JobRepository
+}
+
+type Transactor interface {
+	// Atomic runs fn on repositories bound to a transaction that is committed
+	// when fn returns nil and rolled back otherwise.
+	Atomic(ctx context.Context, fn func(repos *Repositories) error) error
+	// DryRun runs fn on repositories bound to a transaction that is rolled
+	// back afterwards, whatever fn returns.
+	DryRun(ctx context.Context, fn func(repos *Repositories) error) error
+}
+
+// SchemaVersion is the version of the last migration in migrations/, the
+// schema the repositories are written against.
+const SchemaVersion = 14
+
+// StatusRepository reports whether the storage can serve requests.
+type StatusRepository interface {
+	Ping(ctx context.Context) error
+	// SchemaVersion returns the version of the applied migrations and whether
+	// the last of them failed halfway.
+	SchemaVersion(ctx context.Context) (version int64, dirty bool, err error)
+}
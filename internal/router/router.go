@@ -3,22 +3,57 @@ package router
 import (
 	"net/http"
 	"pr-reviewer-service/internal/handler"
+	"pr-reviewer-service/internal/logging"
+	"pr-reviewer-service/internal/metrics"
 )
 
-func NewRouter(h *handler.Handler) http.Handler {
+func NewRouter(h *handler.Handler, m *metrics.Metrics) http.Handler {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/team/add", h.AddTeam)
 	mux.HandleFunc("/team/get", h.GetTeam)
+	mux.HandleFunc("/team/settings", h.GetTeamSettings)
+	mux.HandleFunc("/team/settings/update", h.UpdateTeamSettings)
+	mux.HandleFunc("/team/codeowners", h.GetTeamCodeOwners)
+	mux.HandleFunc("/team/codeowners/update", h.UpdateTeamCodeOwners)
 	mux.HandleFunc("/users/setIsActive", h.SetIsActive)
+	mux.HandleFunc("/users/setMaxOpenReviews", h.SetMaxOpenReviews)
 	mux.HandleFunc("/users/getReview", h.GetUserReviews)
 	mux.HandleFunc("/pullRequest/create", h.CreatePullRequest)
 	mux.HandleFunc("/pullRequest/merge", h.MergePullRequest)
 	mux.HandleFunc("/pullRequest/reassign", h.ReassignPullRequest)
+	mux.HandleFunc("/pullRequest/explain", h.ExplainPullRequest)
+	mux.HandleFunc("/pullRequest/close", h.ClosePullRequest)
+	mux.HandleFunc("/pullRequest/reopen", h.ReopenPullRequest)
+	mux.HandleFunc("/pullRequest/ready", h.MarkPullRequestReady)
+	mux.HandleFunc("/pullRequest/review", h.ReviewPullRequest)
+	mux.HandleFunc("/pullRequest/history", h.GetPullRequestHistory)
 	mux.HandleFunc("/health", h.Health)
+	mux.HandleFunc("/livez", h.Livez)
+	mux.HandleFunc("/readyz", h.Readyz)
 	mux.HandleFunc("/stats", h.GetStatistics)
 	mux.HandleFunc("/users/deactivate", h.DeactivateUsers)
+	mux.HandleFunc("/users/reactivate", h.ReactivateUser)
+	mux.HandleFunc("/jobs/get", h.GetJob)
+	mux.HandleFunc("/jobs/retry", h.RetryJob)
+	mux.HandleFunc("/users/availability", h.GetAvailability)
+	mux.HandleFunc("/users/availability/add", h.AddAvailability)
+	mux.HandleFunc("/users/availability/delete", h.DeleteAvailability)
+	mux.HandleFunc("/users/history", h.GetUserHistory)
+	mux.HandleFunc("/webhooks/add", h.AddWebhook)
+	mux.HandleFunc("/webhooks/list", h.ListWebhooks)
+	mux.HandleFunc("/webhooks/delete", h.DeleteWebhook)
+	mux.HandleFunc("/webhooks/deliveries/dead", h.GetDeadDeliveries)
+	mux.HandleFunc("/webhooks/deliveries/retry", h.RetryDelivery)
+	mux.HandleFunc("/integrations/users", h.GetIntegrationUsers)
+	mux.HandleFunc("/integrations/users/map", h.MapIntegrationUser)
+	mux.HandleFunc("/integrations/users/unmap", h.UnmapIntegrationUser)
+	mux.HandleFunc("/integrations/github/webhook", h.GitHubWebhook)
+	mux.HandleFunc("/integrations/gitlab/webhook", h.GitLabWebhook)
+	mux.HandleFunc("/integrations/gitlab/syncs", h.GetReviewerSyncs)
+	mux.HandleFunc("/integrations/gitlab/syncs/push", h.PushReviewerSync)
+	mux.Handle("/metrics", m.Handler())
 
-	return mux
+	return logging.RequestID(logging.AccessLog(m.Instrument(mux)))
 }
 
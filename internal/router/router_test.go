@@ -0,0 +1,44 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pr-reviewer-service/internal/handler"
+	"pr-reviewer-service/internal/logging"
+	"pr-reviewer-service/internal/metrics"
+)
+
+func TestRouterRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(&buf, slog.LevelInfo))
+	defer slog.SetDefault(previous)
+
+	h := handler.NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	r := NewRouter(h, metrics.New())
+
+	req := httptest.NewRequest(http.MethodGet, "/livez", nil)
+	req.Header.Set(logging.RequestIDHeader, "client-req-42")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got := w.Header().Get(logging.RequestIDHeader); got != "client-req-42" {
+		t.Errorf("response request ID = %q, want the incoming one", got)
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("decode access log %q: %v", buf.String(), err)
+	}
+	if record["msg"] != "request served" || record["request_id"] != "client-req-42" || record["path"] != "/livez" {
+		t.Errorf("access log = %v, want /livez served with the incoming request ID", record)
+	}
+}
@@ -0,0 +1,456 @@
+package service
+
+import (
+	"context"
+	"pr-reviewer-service/internal/codeowners"
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+// assignment is the outcome of reviewer selection: the chosen reviewers, those
+// of them that came from fallback teams, the strategy of the author's team and
+// how many reviewers the team wants.
+type assignment struct {
+	reviewers []string
+	fallback  []string
+	strategy  string
+	wanted    int
+}
+
+// pickReviewers selects reviewers for a new or just opened PR of the author
+// according to the settings of the author's team. When the team has CODEOWNERS
+// rules for the changed files, one slot goes to an owner and the rest to the
+// strategy. Slots the team cannot fill are filled from its fallback teams.
+func (s *PullRequestService) pickReviewers(ctx context.Context, author *models.User, changedFiles []string, e *explainer) (*assignment, error) {
+	settings, err := s.teamRepo.GetSettings(ctx, author.TeamName)
+	if err != nil {
+		if err == repository.ErrTeamNotFound {
+			return nil, ErrTeamNotFound
+		}
+		return nil, err
+	}
+	e.subject(author, settings.ReviewerCount)
+
+	excludeUserID := author.UserID
+	if settings.AllowSelfReview {
+		excludeUserID = ""
+	}
+
+	count := settings.ReviewerCount
+	selector := s.selectors.ForTeam(author.TeamName)
+	result := &assignment{strategy: selector.Name(), wanted: settings.ReviewerCount}
+
+	if count > 0 && len(changedFiles) > 0 {
+		pool := e.pool(models.PoolCodeOwners, author.TeamName, 1)
+		owners, err := s.ownerCandidates(ctx, author.TeamName, changedFiles, excludeUserID, e, pool)
+		if err != nil {
+			return nil, err
+		}
+		available, err := s.filterByCapacity(ctx, owners)
+		if err != nil {
+			return nil, err
+		}
+		e.exclude(pool, owners, available, models.ExclusionAtCapacity)
+
+		if len(available) > 0 {
+			selected, err := e.selectReviewers(ctx, pool, selector, author.TeamName, available, 1)
+			if err != nil {
+				return nil, err
+			}
+			for _, reviewer := range selected {
+				result.reviewers = append(result.reviewers, reviewer.UserID)
+			}
+			count -= len(selected)
+		}
+	}
+
+	pool := e.pool(models.PoolTeam, author.TeamName, count)
+	members, err := s.userRepo.GetActiveUsersByTeam(ctx, author.TeamName, excludeUserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.members(ctx, pool, author.TeamName, members); err != nil {
+		return nil, err
+	}
+
+	available, err := s.filterByCapacity(ctx, members)
+	if err != nil {
+		return nil, err
+	}
+	e.exclude(pool, members, available, models.ExclusionAtCapacity)
+	allAtCapacity := len(members) > 0 && len(available) == 0
+
+	candidates := make([]*models.User, 0, len(available))
+	for _, candidate := range available {
+		if !containsUserID(result.reviewers, candidate.UserID) {
+			candidates = append(candidates, candidate)
+		}
+	}
+	e.exclude(pool, available, candidates, models.ExclusionAlreadyAssigned)
+
+	if len(candidates) > 0 && count > 0 {
+		selected, err := e.selectReviewers(ctx, pool, selector, author.TeamName, candidates, count)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, reviewer := range selected {
+			result.reviewers = append(result.reviewers, reviewer.UserID)
+		}
+	}
+
+	if missing := settings.ReviewerCount - len(result.reviewers); missing > 0 {
+		exclude := make(map[string]bool)
+		if excludeUserID != "" {
+			exclude[excludeUserID] = true
+		}
+		for _, reviewerID := range result.reviewers {
+			exclude[reviewerID] = true
+		}
+
+		picked, err := s.pickFallback(ctx, settings.FallbackTeams, missing, exclude, e)
+		if err != nil {
+			return nil, err
+		}
+		for _, reviewer := range picked {
+			result.reviewers = append(result.reviewers, reviewer.UserID)
+			result.fallback = append(result.fallback, reviewer.UserID)
+		}
+	}
+
+	if allAtCapacity && len(result.reviewers) == 0 && settings.CapacityPolicy == models.CapacityPolicyFail {
+		return nil, ErrAllAtCapacity
+	}
+	if len(result.reviewers) < settings.MinReviewers {
+		return nil, ErrNotEnoughReviewers
+	}
+
+	return result, nil
+}
+
+// pickFallback selects up to count reviewers from fallbackTeams, asking the
+// teams in order until enough are found. Users in exclude are skipped.
+func (s *PullRequestService) pickFallback(
+	ctx context.Context,
+	fallbackTeams []string,
+	count int,
+	exclude map[string]bool,
+	e *explainer,
+) ([]*models.User, error) {
+	var picked []*models.User
+	for _, teamName := range fallbackTeams {
+		if count == 0 {
+			break
+		}
+
+		pool := e.pool(models.PoolFallback, teamName, count)
+		members, err := s.userRepo.GetActiveUsersByTeam(ctx, teamName, "")
+		if err != nil {
+			return nil, err
+		}
+		if err := e.members(ctx, pool, teamName, members); err != nil {
+			return nil, err
+		}
+
+		candidates := make([]*models.User, 0, len(members))
+		for _, member := range members {
+			if !exclude[member.UserID] {
+				candidates = append(candidates, member)
+			}
+		}
+		e.exclude(pool, members, candidates, models.ExclusionAlreadyAssigned)
+
+		available, err := s.filterByCapacity(ctx, candidates)
+		if err != nil {
+			return nil, err
+		}
+		e.exclude(pool, candidates, available, models.ExclusionAtCapacity)
+		if len(available) == 0 {
+			continue
+		}
+
+		selected, err := e.selectReviewers(ctx, pool, s.selectors.ForTeam(teamName), teamName, available, count)
+		if err != nil {
+			return nil, err
+		}
+		for _, reviewer := range selected {
+			picked = append(picked, reviewer)
+			exclude[reviewer.UserID] = true
+		}
+		count -= len(selected)
+	}
+	return picked, nil
+}
+
+// replacement is the reviewer chosen to replace another one.
+type replacement struct {
+	reviewer *models.User
+	fallback bool
+	strategy string
+}
+
+// pickReplacement selects who replaces oldUserID on the PR.
+func (s *PullRequestService) pickReplacement(ctx context.Context, pr *models.PullRequest, oldUserID string, e *explainer) (*replacement, error) {
+	if pr.Status != models.StatusOpen {
+		return nil, statusError(pr.Status)
+	}
+	if !containsUserID(pr.AssignedReviewers, oldUserID) {
+		return nil, ErrReviewerNotAssigned
+	}
+
+	oldReviewer, err := s.userRepo.GetByID(ctx, oldUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.teamRepo.GetSettings(ctx, author.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	e.subject(author, 1)
+	e.replacing(pr.PullRequestID, oldUserID)
+
+	// Code owners and fallback reviewers from other teams are replaced from
+	// the author's team.
+	teamName := oldReviewer.TeamName
+	if (len(pr.ChangedFiles) > 0 || containsUserID(pr.FallbackReviewers, oldUserID)) && teamName != author.TeamName {
+		teamName = author.TeamName
+	}
+	selector := s.selectors.ForTeam(teamName)
+
+	if len(pr.ChangedFiles) > 0 {
+		pool := e.pool(models.PoolCodeOwners, author.TeamName, 1)
+		owners, err := s.replacementOwners(ctx, pr, author.TeamName, oldUserID, settings.AllowSelfReview, e, pool)
+		if err != nil {
+			return nil, err
+		}
+		if len(owners) > 0 {
+			selected, err := e.selectReviewers(ctx, pool, selector, teamName, owners, 1)
+			if err != nil {
+				return nil, err
+			}
+			return &replacement{reviewer: selected[0], strategy: selector.Name()}, nil
+		}
+	}
+
+	pool := e.pool(models.PoolTeam, teamName, 1)
+	members, err := s.userRepo.GetActiveUsersByTeam(ctx, teamName, oldUserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.members(ctx, pool, teamName, members); err != nil {
+		return nil, err
+	}
+
+	candidates := make([]*models.User, 0, len(members))
+	for _, candidate := range members {
+		if candidate.UserID != pr.AuthorID || settings.AllowSelfReview {
+			candidates = append(candidates, candidate)
+		}
+	}
+	e.exclude(pool, members, candidates, models.ExclusionAuthor)
+
+	filtered := make([]*models.User, 0, len(candidates))
+	for _, candidate := range candidates {
+		if !containsUserID(pr.AssignedReviewers, candidate.UserID) {
+			filtered = append(filtered, candidate)
+		}
+	}
+	e.exclude(pool, candidates, filtered, models.ExclusionAlreadyAssigned)
+	candidates = filtered
+
+	hadCandidates := len(candidates) > 0
+	available, err := s.filterByCapacity(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+	e.exclude(pool, candidates, available, models.ExclusionAtCapacity)
+
+	if len(available) > 0 {
+		selected, err := e.selectReviewers(ctx, pool, selector, teamName, available, 1)
+		if err != nil {
+			return nil, err
+		}
+		return &replacement{reviewer: selected[0], strategy: selector.Name()}, nil
+	}
+
+	fallbackTeams := settings.FallbackTeams
+	if teamName != author.TeamName {
+		teamSettings, err := s.teamRepo.GetSettings(ctx, teamName)
+		if err != nil {
+			return nil, err
+		}
+		fallbackTeams = teamSettings.FallbackTeams
+	}
+
+	exclude := map[string]bool{oldUserID: true}
+	for _, reviewerID := range pr.AssignedReviewers {
+		exclude[reviewerID] = true
+	}
+	if !settings.AllowSelfReview {
+		exclude[pr.AuthorID] = true
+	}
+
+	picked, err := s.pickFallback(ctx, fallbackTeams, 1, exclude, e)
+	if err != nil {
+		return nil, err
+	}
+	if len(picked) == 0 {
+		if hadCandidates && settings.CapacityPolicy == models.CapacityPolicyFail {
+			return nil, ErrAllAtCapacity
+		}
+		return nil, ErrNoCandidate
+	}
+	return &replacement{
+		reviewer: picked[0],
+		fallback: true,
+		strategy: s.selectors.ForTeam(picked[0].TeamName).Name(),
+	}, nil
+}
+
+// ownerCandidates resolves code owners to active users, who may be in other teams.
+func (s *PullRequestService) ownerCandidates(
+	ctx context.Context,
+	teamName string,
+	changedFiles []string,
+	excludeUserID string,
+	e *explainer,
+	pool *models.CandidatePool,
+) ([]*models.User, error) {
+	if len(changedFiles) == 0 {
+		return nil, nil
+	}
+
+	codeOwners, err := s.teamRepo.GetCodeOwners(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if codeOwners.Content == "" {
+		return nil, nil
+	}
+	ruleset, err := codeowners.Parse(codeOwners.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	activeByTeam := make(map[string][]*models.User)
+	activeMembers := func(team string) ([]*models.User, error) {
+		if members, ok := activeByTeam[team]; ok {
+			return members, nil
+		}
+		members, err := s.userRepo.GetActiveUsersByTeam(ctx, team, "")
+		if err != nil {
+			return nil, err
+		}
+		activeByTeam[team] = members
+		return members, nil
+	}
+
+	seen := make(map[string]bool)
+	users := make([]*models.User, 0)
+	for _, owner := range ruleset.OwnersOf(changedFiles) {
+		var members []*models.User
+		if owner.TeamName != "" {
+			members, err = activeMembers(owner.TeamName)
+			if err != nil {
+				return nil, err
+			}
+			if err := e.members(ctx, pool, owner.TeamName, members); err != nil {
+				return nil, err
+			}
+		} else {
+			user, err := s.userRepo.GetByID(ctx, owner.UserID)
+			if err == repository.ErrUserNotFound {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			active, err := activeMembers(user.TeamName)
+			if err != nil {
+				return nil, err
+			}
+			for _, member := range active {
+				if member.UserID == user.UserID {
+					members = append(members, member)
+				}
+			}
+			if len(members) == 0 {
+				e.excludeUser(pool, user, "")
+			}
+		}
+
+		for _, member := range members {
+			if seen[member.UserID] {
+				continue
+			}
+			seen[member.UserID] = true
+			if member.UserID == excludeUserID {
+				e.excludeUser(pool, member, "")
+				continue
+			}
+			users = append(users, member)
+		}
+	}
+	return users, nil
+}
+
+// replacementOwners is empty while another assigned reviewer owns the files.
+func (s *PullRequestService) replacementOwners(
+	ctx context.Context,
+	pr *models.PullRequest,
+	teamName, oldUserID string,
+	allowSelfReview bool,
+	e *explainer,
+	pool *models.CandidatePool,
+) ([]*models.User, error) {
+	owners, err := s.ownerCandidates(ctx, teamName, pr.ChangedFiles, oldUserID, e, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	available := make([]*models.User, 0, len(owners))
+	for _, owner := range owners {
+		if containsUserID(pr.AssignedReviewers, owner.UserID) {
+			e.excludeUser(pool, owner, models.ExclusionAlreadyAssigned)
+			return nil, nil
+		}
+		if owner.UserID != pr.AuthorID || allowSelfReview {
+			available = append(available, owner)
+		}
+	}
+	e.exclude(pool, owners, available, models.ExclusionAuthor)
+
+	candidates, err := s.filterByCapacity(ctx, available)
+	if err != nil {
+		return nil, err
+	}
+	e.exclude(pool, available, candidates, models.ExclusionAtCapacity)
+	return candidates, nil
+}
+
+func (s *PullRequestService) keepsOwner(ctx context.Context, pr *models.PullRequest, teamName, fromUserID, toUserID string) (bool, error) {
+	owners, err := s.ownerCandidates(ctx, teamName, pr.ChangedFiles, "", nil, nil)
+	if err != nil {
+		return false, err
+	}
+
+	fromOwner := false
+	for _, owner := range owners {
+		switch {
+		case owner.UserID == toUserID:
+			return true, nil
+		case owner.UserID == fromUserID:
+			fromOwner = true
+		case containsUserID(pr.AssignedReviewers, owner.UserID):
+			return true, nil
+		}
+	}
+	return !fromOwner, nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"pr-reviewer-service/internal/logging"
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+var (
+	ErrInvalidAvailability = errors.New("ends_at must be after starts_at")
+)
+
+type AvailabilityService struct {
+	availabilityRepo repository.AvailabilityRepository
+	userRepo         repository.UserRepository
+	prRepo           repository.PullRequestRepository
+	prService        *PullRequestService
+	heartbeat        *Heartbeat
+}
+
+func NewAvailabilityService(
+	availabilityRepo repository.AvailabilityRepository,
+	userRepo repository.UserRepository,
+	prRepo repository.PullRequestRepository,
+	prService *PullRequestService,
+) *AvailabilityService {
+	return &AvailabilityService{
+		availabilityRepo: availabilityRepo,
+		userRepo:         userRepo,
+		prRepo:           prRepo,
+		prService:        prService,
+		heartbeat:        newHeartbeat("availability_sweep"),
+	}
+}
+
+func (s *AvailabilityService) AddAvailability(ctx context.Context, a *models.Availability) (*models.AvailabilityResponse, error) {
+	ctx = logging.With(ctx, "user_id", a.UserID)
+	if !a.EndsAt.After(a.StartsAt) {
+		return nil, ErrInvalidAvailability
+	}
+
+	_, err := s.userRepo.GetByID(ctx, a.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.availabilityRepo.Create(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.AvailabilityResponse{Availability: a}
+	if a.ReassignReviews && a.Covers(time.Now()) {
+		response.ReassignedPRs, response.FailedReassignments, err = s.reassignReviews(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+func (s *AvailabilityService) GetAvailability(ctx context.Context, userID string) ([]*models.Availability, error) {
+	ctx = logging.With(ctx, "user_id", userID)
+	_, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	windows, err := s.availabilityRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if windows == nil {
+		windows = []*models.Availability{}
+	}
+	return windows, nil
+}
+
+func (s *AvailabilityService) DeleteAvailability(ctx context.Context, availabilityID int64) error {
+	return s.availabilityRepo.Delete(ctx, availabilityID)
+}
+
+// ReassignStartedAbsences hands over open reviews of users whose absence with
+// reassign_reviews has already started. Every window is processed once.
+func (s *AvailabilityService) ReassignStartedAbsences(ctx context.Context) error {
+	windows, err := s.availabilityRepo.GetStartedPendingReassignment(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range windows {
+		reassigned, failed, err := s.reassignReviews(ctx, a)
+		if err != nil {
+			return err
+		}
+		if len(reassigned) > 0 || len(failed) > 0 {
+			slog.InfoContext(ctx, "reassigned reviews of absent user",
+				"availability_id", a.AvailabilityID, "user_id", a.UserID, "reassigned", reassigned, "failed", failed)
+		}
+	}
+	return nil
+}
+
+// Heartbeat reports the progress of Run to the readiness check.
+func (s *AvailabilityService) Heartbeat() *Heartbeat {
+	return s.heartbeat
+}
+
+// Run hands over reviews of started absences every interval until ctx is
+// done. A pass in progress when ctx is done is finished first.
+func (s *AvailabilityService) Run(ctx context.Context, interval time.Duration) {
+	work := logging.With(context.WithoutCancel(ctx), "worker", s.heartbeat.Name())
+	defer s.heartbeat.run()()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			done := s.heartbeat.pass()
+			if err := s.ReassignStartedAbsences(work); err != nil {
+				slog.ErrorContext(work, "failed to reassign reviews of absent users", "error", err)
+			}
+			done()
+		}
+	}
+}
+
+func (s *AvailabilityService) reassignReviews(ctx context.Context, a *models.Availability) ([]string, []string, error) {
+	ctx = logging.With(ctx, "availability_id", a.AvailabilityID, "user_id", a.UserID)
+	openPRs, err := s.prRepo.GetOpenPRsWithReviewer(ctx, a.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reassigned := make([]string, 0)
+	failed := make([]string, 0)
+	// Without a candidate the review stays with the absent user; other failures
+	// may pass, so the window is left for the next sweep to retry.
+	retry := false
+	for _, pr := range openPRs {
+		_, _, err := s.prService.ReassignReviewer(ctx, pr.PullRequestID, a.UserID, ActorSystem)
+		if err != nil {
+			failed = append(failed, pr.PullRequestID)
+			if err != ErrNoCandidate {
+				retry = true
+				slog.WarnContext(ctx, "failed to reassign review of absent user", "pull_request_id", pr.PullRequestID, "error", err)
+			}
+		} else {
+			reassigned = append(reassigned, pr.PullRequestID)
+		}
+	}
+
+	if !retry {
+		err = s.availabilityRepo.MarkReviewsReassigned(ctx, a.AvailabilityID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return reassigned, failed, nil
+}
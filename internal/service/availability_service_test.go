@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository/memory"
+)
+
+// startedAbsence stores a window that has already started without handing
+// over the reviews, as if it started after it was added.
+func startedAbsence(t *testing.T, e *testEnv, userID string, from, to time.Duration) *models.Availability {
+	t.Helper()
+	a := &models.Availability{
+		UserID:          userID,
+		StartsAt:        time.Now().Add(from),
+		EndsAt:          time.Now().Add(to),
+		Reason:          "vacation",
+		ReassignReviews: true,
+	}
+	if err := memory.NewAvailabilityRepository(e.store).Create(context.Background(), a); err != nil {
+		t.Fatalf("Create availability: %v", err)
+	}
+	return a
+}
+
+func reviewsReassigned(t *testing.T, e *testEnv, userID string) map[int64]bool {
+	t.Helper()
+	windows, err := e.availability.GetAvailability(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("GetAvailability: %v", err)
+	}
+	marked := make(map[int64]bool)
+	for _, a := range windows {
+		marked[a.AvailabilityID] = a.ReviewsReassignedAt != nil
+	}
+	return marked
+}
+
+func TestReassignStartedAbsences(t *testing.T) {
+	tests := []struct {
+		name          string
+		setup         func(t *testing.T, e *testEnv)
+		wantReviewers []string
+		wantMarked    bool
+	}{
+		{
+			name:          "replaced",
+			wantReviewers: []string{"dave", "carol"},
+			wantMarked:    true,
+		},
+		{
+			name: "no candidate",
+			setup: func(t *testing.T, e *testEnv) {
+				if err := e.repos.Users.SetIsActive(context.Background(), "dave", false); err != nil {
+					t.Fatalf("SetIsActive: %v", err)
+				}
+			},
+			wantReviewers: []string{"bob", "carol"},
+			wantMarked:    true,
+		},
+		{
+			name: "everyone at capacity",
+			setup: func(t *testing.T, e *testEnv) {
+				e.setCapacity(t, "dave", 0)
+				e.updateSettings(t, "backend", func(settings *models.TeamSettings) {
+					settings.CapacityPolicy = models.CapacityPolicyFail
+				})
+			},
+			wantReviewers: []string{"bob", "carol"},
+			wantMarked:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			e := newReviewEnv(t)
+			if tt.setup != nil {
+				tt.setup(t, e)
+			}
+			a := startedAbsence(t, e, "bob", -time.Hour, time.Hour)
+
+			if err := e.availability.ReassignStartedAbsences(ctx); err != nil {
+				t.Fatalf("ReassignStartedAbsences: %v", err)
+			}
+			if pr := e.getPR(t, "pr-1"); !sameUserIDs(pr.AssignedReviewers, tt.wantReviewers) {
+				t.Errorf("reviewers = %v, want %v", pr.AssignedReviewers, tt.wantReviewers)
+			}
+			if got := reviewsReassigned(t, e, "bob")[a.AvailabilityID]; got != tt.wantMarked {
+				t.Errorf("window marked = %v, want %v", got, tt.wantMarked)
+			}
+		})
+	}
+}
+
+func TestReassignStartedAbsencesRetriesUnmarkedWindow(t *testing.T) {
+	ctx := context.Background()
+	e := newReviewEnv(t)
+	e.setCapacity(t, "dave", 0)
+	e.updateSettings(t, "backend", func(settings *models.TeamSettings) {
+		settings.CapacityPolicy = models.CapacityPolicyFail
+	})
+	a := startedAbsence(t, e, "bob", -time.Hour, time.Hour)
+
+	if err := e.availability.ReassignStartedAbsences(ctx); err != nil {
+		t.Fatalf("ReassignStartedAbsences: %v", err)
+	}
+	e.setCapacity(t, "dave", 1)
+	if err := e.availability.ReassignStartedAbsences(ctx); err != nil {
+		t.Fatalf("ReassignStartedAbsences: %v", err)
+	}
+
+	if pr := e.getPR(t, "pr-1"); !sameUserIDs(pr.AssignedReviewers, []string{"dave", "carol"}) {
+		t.Errorf("reviewers = %v, want bob replaced once dave has capacity", pr.AssignedReviewers)
+	}
+	if !reviewsReassigned(t, e, "bob")[a.AvailabilityID] {
+		t.Error("window is not marked after the retry")
+	}
+}
+
+func TestOverlappingAbsences(t *testing.T) {
+	ctx := context.Background()
+	e := newReviewEnv(t)
+	first := startedAbsence(t, e, "bob", -2*time.Hour, time.Hour)
+	second := startedAbsence(t, e, "bob", -time.Hour, 2*time.Hour)
+
+	if err := e.availability.ReassignStartedAbsences(ctx); err != nil {
+		t.Fatalf("ReassignStartedAbsences: %v", err)
+	}
+	if err := e.availability.ReassignStartedAbsences(ctx); err != nil {
+		t.Fatalf("ReassignStartedAbsences: %v", err)
+	}
+	if pr := e.getPR(t, "pr-1"); !sameUserIDs(pr.AssignedReviewers, []string{"dave", "carol"}) {
+		t.Errorf("reviewers = %v, want bob replaced by dave", pr.AssignedReviewers)
+	}
+	if got := e.notifier.count(models.WebhookPRReassigned); got != 1 {
+		t.Errorf("%s notifications = %d, want the review handed over once", models.WebhookPRReassigned, got)
+	}
+	marked := reviewsReassigned(t, e, "bob")
+	if !marked[first.AvailabilityID] || !marked[second.AvailabilityID] {
+		t.Errorf("windows marked = %v, want both", marked)
+	}
+
+	// bob stays away while the other window lasts.
+	if err := e.availability.DeleteAvailability(ctx, first.AvailabilityID); err != nil {
+		t.Fatalf("DeleteAvailability: %v", err)
+	}
+	users, err := e.repos.Users.GetActiveUsersByTeam(ctx, "backend", "")
+	if err != nil {
+		t.Fatalf("GetActiveUsersByTeam: %v", err)
+	}
+	for _, user := range users {
+		if user.UserID == "bob" {
+			t.Error("bob is available while the second window covers now")
+		}
+	}
+}
+
+func TestAddAvailability(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name         string
+		window       models.Availability
+		wantErr      error
+		wantReviewer string
+	}{
+		{
+			name:         "started with reassignment",
+			window:       models.Availability{UserID: "bob", StartsAt: time.Now().Add(-time.Minute), EndsAt: time.Now().Add(time.Hour), ReassignReviews: true},
+			wantReviewer: "dave",
+		},
+		{
+			name:         "started without reassignment",
+			window:       models.Availability{UserID: "bob", StartsAt: time.Now().Add(-time.Minute), EndsAt: time.Now().Add(time.Hour)},
+			wantReviewer: "bob",
+		},
+		{
+			name:         "not started yet",
+			window:       models.Availability{UserID: "bob", StartsAt: time.Now().Add(time.Hour), EndsAt: time.Now().Add(2 * time.Hour), ReassignReviews: true},
+			wantReviewer: "bob",
+		},
+		{
+			name:    "ends before it starts",
+			window:  models.Availability{UserID: "bob", StartsAt: time.Now(), EndsAt: time.Now().Add(-time.Hour)},
+			wantErr: ErrInvalidAvailability,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newReviewEnv(t)
+			window := tt.window
+			_, err := e.availability.AddAvailability(ctx, &window)
+			if err != tt.wantErr {
+				t.Fatalf("AddAvailability error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if pr := e.getPR(t, "pr-1"); pr.AssignedReviewers[0] != tt.wantReviewer {
+				t.Errorf("reviewers = %v, want %s in bob's place", pr.AssignedReviewers, tt.wantReviewer)
+			}
+		})
+	}
+}
@@ -1,47 +1,95 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
+	"pr-reviewer-service/internal/logging"
 	"pr-reviewer-service/internal/models"
 	"pr-reviewer-service/internal/repository"
 )
 
+var ErrReassignmentFailed = errors.New("some open reviews cannot be reassigned")
+
+const (
+	jobBatchSize = 50
+	// jobLease must outlive a single reassignment, otherwise another worker
+	// may claim the item while it is still being processed.
+	jobLease = time.Minute
+)
+
 type DeactivationService struct {
-	userRepo  *repository.UserRepository
-	prRepo    *repository.PullRequestRepository
+	userRepo  repository.UserRepository
+	teamRepo  repository.TeamRepository
+	prRepo    repository.PullRequestRepository
+	eventRepo repository.EventRepository
+	jobRepo   repository.JobRepository
 	prService *PullRequestService
+	notifier  Notifier
+	policy    RetryPolicy
+	heartbeat *Heartbeat
 }
 
 func NewDeactivationService(
-	userRepo *repository.UserRepository,
-	prRepo *repository.PullRequestRepository,
+	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+	prRepo repository.PullRequestRepository,
+	eventRepo repository.EventRepository,
+	jobRepo repository.JobRepository,
 	prService *PullRequestService,
+	notifier Notifier,
+	policy RetryPolicy,
 ) *DeactivationService {
 	return &DeactivationService{
 		userRepo:  userRepo,
+		teamRepo:  teamRepo,
 		prRepo:    prRepo,
+		eventRepo: eventRepo,
+		jobRepo:   jobRepo,
 		prService: prService,
+		notifier:  notifier,
+		policy:    policy,
+		heartbeat: newHeartbeat("deactivation_jobs"),
 	}
 }
 
-func (s *DeactivationService) DeactivateUsers(teamName string, userIDs []string) (*models.DeactivationResponse, error) {
-	startTime := time.Now()
+// DeactivateUsers deactivates the listed team members right away and queues a
+// job that hands their open reviews over. The deactivation, its events and the
+// job are committed together. The job is processed by Run and can be followed
+// with GetJob.
+func (s *DeactivationService) DeactivateUsers(ctx context.Context, teamName string, userIDs []string, actor string) (*models.DeactivationResponse, error) {
+	notifier := &deferredNotifier{target: s.notifier}
 
-	_, err := s.userRepo.GetUsersByTeam(teamName)
+	var response *models.DeactivationResponse
+	err := s.prService.transactor.Atomic(ctx, func(repos *repository.Repositories) error {
+		var err error
+		response, err = s.bind(repos, notifier, false).deactivate(ctx, teamName, userIDs, actor)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get team: %w", err)
+		return nil, err
+	}
+
+	if err := notifier.flush(); err != nil {
+		return nil, err
 	}
+	return response, nil
+}
 
-	teamUsers, err := s.userRepo.GetUsersByTeam(teamName)
+// deactivate does not start a transaction of its own.
+func (s *DeactivationService) deactivate(ctx context.Context, teamName string, userIDs []string, actor string) (*models.DeactivationResponse, error) {
+	ctx = logging.With(ctx, "team_name", teamName)
+	team, err := s.teamRepo.GetByName(ctx, teamName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get team users: %w", err)
+		return nil, fmt.Errorf("failed to get team: %w", err)
 	}
 
 	userMap := make(map[string]bool)
-	for _, u := range teamUsers {
-		userMap[u.UserID] = true
+	for _, member := range team.Members {
+		userMap[member.UserID] = true
 	}
 
 	validUserIDs := make([]string, 0)
@@ -59,40 +107,295 @@ func (s *DeactivationService) DeactivateUsers(teamName string, userIDs []string)
 		}, nil
 	}
 
-	err = s.userRepo.BulkSetIsActive(validUserIDs, false)
+	err = s.userRepo.BulkSetIsActive(ctx, validUserIDs, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to deactivate users: %w", err)
 	}
 
-	reassignedPRs := make([]string, 0)
-	failedReassignments := make([]string, 0)
-
 	for _, userID := range validUserIDs {
-		openPRs, err := s.prRepo.GetOpenPRsWithReviewer(userID)
+		err = s.eventRepo.Append(ctx, &models.Event{
+			EventType: models.EventUserDeactivated,
+			UserID:    userID,
+			Actor:     actor,
+			Details:   teamName,
+		})
 		if err != nil {
-			continue
+			return nil, fmt.Errorf("failed to record deactivation of user %s: %w", userID, err)
 		}
+	}
 
+	job := &models.Job{
+		JobType:  models.JobTypeDeactivation,
+		TeamName: teamName,
+		UserIDs:  validUserIDs,
+		Actor:    actor,
+		Items:    make([]*models.JobItem, 0),
+	}
+	for _, userID := range validUserIDs {
+		openPRs, err := s.prRepo.GetOpenPRsWithReviewer(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get open PRs of user %s: %w", userID, err)
+		}
 		for _, pr := range openPRs {
-			if time.Since(startTime) > 90*time.Millisecond {
-				failedReassignments = append(failedReassignments, pr.PullRequestID)
-				continue
-			}
+			job.Items = append(job.Items, &models.JobItem{PullRequestID: pr.PullRequestID, UserID: userID})
+		}
+	}
+
+	err = s.jobRepo.CreateJob(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create deactivation job: %w", err)
+	}
+	if job.Status == models.JobCompleted {
+		err = s.notifier.Notify(ctx, models.WebhookUsersDeactivated, job)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newDeactivationResponse(teamName, validUserIDs, job), nil
+}
+
+func newDeactivationResponse(teamName string, userIDs []string, job *models.Job) *models.DeactivationResponse {
+	response := &models.DeactivationResponse{
+		TeamName:         teamName,
+		DeactivatedUsers: userIDs,
+		ReassignedPRs:    make([]string, 0),
+		Job:              job,
+	}
+	for _, item := range job.Items {
+		switch item.Status {
+		case models.JobItemDone:
+			response.ReassignedPRs = append(response.ReassignedPRs, item.PullRequestID)
+		case models.JobItemFailed:
+			response.FailedReassignments = append(response.FailedReassignments, item.PullRequestID)
+		}
+	}
+	return response
+}
+
+// DeactivateUsersAtomic deactivates the users and replaces them on all their
+// open PRs in a single transaction. If any PR cannot get a replacement, nothing
+// is changed and ErrReassignmentFailed is returned together with the outcome for
+// every PR in Reassignments. Webhooks are sent only after the transaction is
+// committed.
+func (s *DeactivationService) DeactivateUsersAtomic(ctx context.Context, teamName string, userIDs []string, actor string) (*models.DeactivationResponse, error) {
+	notifier := &deferredNotifier{target: s.notifier}
+
+	var response *models.DeactivationResponse
+	err := s.prService.transactor.Atomic(ctx, func(repos *repository.Repositories) error {
+		var err error
+		response, err = s.bind(repos, notifier, false).deactivateNow(ctx, teamName, userIDs, actor)
+		if err != nil {
+			return err
+		}
+		if response.Job != nil && response.Job.Progress.Failed > 0 {
+			return ErrReassignmentFailed
+		}
+		return nil
+	})
+	if err == ErrReassignmentFailed {
+		// The job was rolled back with everything else, only its report is kept.
+		response.Reassignments = make([]*models.Reassignment, 0, len(response.Job.Items))
+		for _, item := range response.Job.Items {
+			response.Reassignments = append(response.Reassignments, &models.Reassignment{
+				PullRequestID: item.PullRequestID,
+				UserID:        item.UserID,
+				Status:        item.Status,
+				ReplacedBy:    item.ReplacedBy,
+				Error:         item.LastError,
+			})
+		}
+		response.Job = nil
+		return response, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := notifier.flush(); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// DeactivateUsersDryRun reports what DeactivateUsers, or DeactivateUsersAtomic
+// when atomic is set, would do without changing anything. The reassignments
+// that would fail are reported as FAILED items.
+func (s *DeactivationService) DeactivateUsersDryRun(
+	ctx context.Context,
+	teamName string,
+	userIDs []string,
+	actor string,
+	atomic bool,
+) (*models.DeactivationResponse, error) {
+	var response *models.DeactivationResponse
+	err := s.prService.transactor.DryRun(ctx, func(repos *repository.Repositories) error {
+		var err error
+		response, err = s.bind(repos, discardNotifier{}, true).deactivateNow(ctx, teamName, userIDs, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response.DryRun = true
+	if atomic && response.Job != nil && response.Job.Progress.Failed > 0 {
+		return response, ErrReassignmentFailed
+	}
+	return response, nil
+}
+
+func (s *DeactivationService) deactivateNow(ctx context.Context, teamName string, userIDs []string, actor string) (*models.DeactivationResponse, error) {
+	response, err := s.deactivate(ctx, teamName, userIDs, actor)
+	if err != nil || response.Job == nil {
+		return response, err
+	}
+
+	for _, item := range response.Job.Items {
+		if err := s.attempt(ctx, item); err != nil {
+			return nil, err
+		}
+	}
+
+	job, err := s.jobRepo.GetJob(ctx, response.Job.JobID)
+	if err != nil {
+		return nil, err
+	}
+	return newDeactivationResponse(teamName, response.DeactivatedUsers, job), nil
+}
+
+// bind returns a copy on repos that gives up on an item after one attempt.
+func (s *DeactivationService) bind(repos *repository.Repositories, notifier Notifier, dryRun bool) *DeactivationService {
+	return &DeactivationService{
+		userRepo:  repos.Users,
+		teamRepo:  repos.Teams,
+		prRepo:    repos.PullRequests,
+		eventRepo: repos.Events,
+		jobRepo:   repos.Jobs,
+		prService: s.prService.bind(repos, notifier, dryRun),
+		notifier:  notifier,
+		policy:    RetryPolicy{MaxAttempts: 1},
+	}
+}
+
+func (s *DeactivationService) GetJob(ctx context.Context, jobID int64) (*models.Job, error) {
+	ctx = logging.With(ctx, "job_id", jobID)
+	return s.jobRepo.GetJob(ctx, jobID)
+}
+
+// RetryJob returns the FAILED items of a finished job to the queue, so Run
+// attempts them again with a fresh retry budget. ErrJobNotFound is returned
+// when the job has no FAILED items.
+func (s *DeactivationService) RetryJob(ctx context.Context, jobID int64) (*models.Job, error) {
+	ctx = logging.With(ctx, "job_id", jobID)
+	err := s.jobRepo.RequeueFailedItems(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return s.jobRepo.GetJob(ctx, jobID)
+}
+
+// ProcessDue attempts the job items whose time has come and returns how many
+// of them were attempted.
+func (s *DeactivationService) ProcessDue(ctx context.Context) (int, error) {
+	items, err := s.jobRepo.ClaimDueItems(ctx, jobBatchSize, jobLease)
+	if err != nil {
+		return 0, err
+	}
 
-			_, _, err := s.prService.ReassignReviewer(pr.PullRequestID, userID)
-			if err != nil {
-				failedReassignments = append(failedReassignments, pr.PullRequestID)
-			} else {
-				reassignedPRs = append(reassignedPRs, pr.PullRequestID)
+	for i, item := range items {
+		if err := s.attempt(ctx, item); err != nil {
+			return i, err
+		}
+	}
+	return len(items), nil
+}
+
+// Heartbeat reports the progress of Run to the readiness check.
+func (s *DeactivationService) Heartbeat() *Heartbeat {
+	return s.heartbeat
+}
+
+// Run attempts due job items every interval until ctx is done. A batch in
+// progress when ctx is done is finished first.
+func (s *DeactivationService) Run(ctx context.Context, interval time.Duration) {
+	work := logging.With(context.WithoutCancel(ctx), "worker", s.heartbeat.Name())
+	defer s.heartbeat.run()()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			done := s.heartbeat.pass()
+			if _, err := s.ProcessDue(work); err != nil {
+				slog.ErrorContext(work, "failed to process deactivation jobs", "error", err)
 			}
+			done()
+		}
+	}
+}
+
+// attempt skips items whose PR no longer needs the replacement.
+func (s *DeactivationService) attempt(ctx context.Context, item *models.JobItem) error {
+	ctx = logging.With(ctx, "job_id", item.JobID, "pull_request_id", item.PullRequestID, "user_id", item.UserID)
+	// The reassignment is reported only after the item is saved, so a failed
+	// notification does not turn a replaced reviewer into a failed item.
+	notifier := &deferredNotifier{target: s.prService.notifier}
+	_, newUserID, reassignErr := s.prService.notifying(notifier).ReassignReviewer(ctx, item.PullRequestID, item.UserID, item.Actor)
+
+	item.Attempts++
+	switch {
+	case reassignErr == nil:
+		item.Status = models.JobItemDone
+		item.ReplacedBy = newUserID
+		item.LastError = ""
+		s.prService.metrics.DeactivationReassignment(ReassignmentSuccess)
+	case isObsoleteReassignment(reassignErr):
+		item.Status = models.JobItemSkipped
+		item.LastError = reassignErr.Error()
+		s.prService.metrics.DeactivationReassignment(ReassignmentSkipped)
+	default:
+		item.LastError = reassignErr.Error()
+		s.prService.metrics.DeactivationReassignment(ReassignmentFailure)
+		if item.Attempts >= s.policy.MaxAttempts {
+			item.Status = models.JobItemFailed
+		} else {
+			item.NextAttemptAt = time.Now().Add(s.policy.Backoff(item.Attempts))
 		}
 	}
 
-	return &models.DeactivationResponse{
-		TeamName:            teamName,
-		DeactivatedUsers:    validUserIDs,
-		ReassignedPRs:       reassignedPRs,
-		FailedReassignments: failedReassignments,
-	}, nil
+	err := s.jobRepo.SaveItemAttempt(ctx, item)
+	if err != nil {
+		return err
+	}
+	if item.Status != models.JobItemPending {
+		err = s.finish(ctx, item.JobID)
+	}
+	return errors.Join(err, notifier.flush())
 }
 
+// finish completes the job after its last item and notifies subscribers.
+func (s *DeactivationService) finish(ctx context.Context, jobID int64) error {
+	finished, err := s.jobRepo.FinishJob(ctx, jobID)
+	if err != nil || !finished {
+		return err
+	}
+
+	job, err := s.jobRepo.GetJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	return s.notifier.Notify(ctx, models.WebhookUsersDeactivated, job)
+}
+
+func isObsoleteReassignment(err error) bool {
+	switch err {
+	case repository.ErrPRNotFound, repository.ErrNotAssigned, ErrReviewerNotAssigned, ErrPRMerged, ErrPRClosed, ErrPRDraft:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+func TestDeactivateUsersQueuesJob(t *testing.T) {
+	ctx := context.Background()
+	e := newReviewEnv(t)
+
+	response, err := e.deactivation.DeactivateUsers(ctx, "backend", []string{"bob", "nobody"}, "admin")
+	if err != nil {
+		t.Fatalf("DeactivateUsers: %v", err)
+	}
+	if !sameUserIDs(response.DeactivatedUsers, []string{"bob"}) || len(response.ReassignedPRs) != 0 {
+		t.Fatalf("response = %+v, want bob deactivated and nothing reassigned yet", response)
+	}
+	if response.Job == nil || response.Job.Status != models.JobRunning || response.Job.Progress.Pending != 1 {
+		t.Fatalf("job = %+v, want one pending item", response.Job)
+	}
+
+	// The deactivation, its event and the job are committed together.
+	if user, _ := e.repos.Users.GetByID(ctx, "bob"); user.IsActive {
+		t.Error("bob is still active")
+	}
+	events, err := e.repos.Events.GetByUser(ctx, "bob")
+	if err != nil {
+		t.Fatalf("GetByUser: %v", err)
+	}
+	if last := events[len(events)-1]; last.EventType != models.EventUserDeactivated || last.Actor != "admin" {
+		t.Errorf("last event = %+v, want deactivation by admin", last)
+	}
+
+	if n, err := e.deactivation.ProcessDue(ctx); err != nil || n != 1 {
+		t.Fatalf("ProcessDue = %d, %v; want 1", n, err)
+	}
+	job, err := e.deactivation.GetJob(ctx, response.Job.JobID)
+	if err != nil {
+		t.Fatalf("GetJob: %v", err)
+	}
+	if job.Status != models.JobCompleted || job.Items[0].ReplacedBy != "dave" {
+		t.Errorf("job = %+v, want bob replaced by dave", job)
+	}
+	if got := e.notifier.count(models.WebhookUsersDeactivated); got != 1 {
+		t.Errorf("%s notifications = %d, want 1", models.WebhookUsersDeactivated, got)
+	}
+}
+
+func TestDeactivateUsersUnknownTeam(t *testing.T) {
+	e := newReviewEnv(t)
+	_, err := e.deactivation.DeactivateUsers(context.Background(), "frontend", []string{"bob"}, "admin")
+	if !errors.Is(err, repository.ErrTeamNotFound) {
+		t.Fatalf("DeactivateUsers error = %v, want %v", err, repository.ErrTeamNotFound)
+	}
+}
+
+func TestDeactivateUsersReportsSynchronousModes(t *testing.T) {
+	tests := []struct {
+		name       string
+		deactivate func(ctx context.Context, e *testEnv) (*models.DeactivationResponse, error)
+		wantActive bool
+	}{
+		{
+			name: "atomic",
+			deactivate: func(ctx context.Context, e *testEnv) (*models.DeactivationResponse, error) {
+				return e.deactivation.DeactivateUsersAtomic(ctx, "backend", []string{"bob"}, "admin")
+			},
+		},
+		{
+			name: "dry run",
+			deactivate: func(ctx context.Context, e *testEnv) (*models.DeactivationResponse, error) {
+				return e.deactivation.DeactivateUsersDryRun(ctx, "backend", []string{"bob"}, "admin", false)
+			},
+			wantActive: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			e := newReviewEnv(t)
+
+			response, err := tt.deactivate(ctx, e)
+			if err != nil {
+				t.Fatalf("deactivate: %v", err)
+			}
+			if !sameUserIDs(response.ReassignedPRs, []string{"pr-1"}) || len(response.FailedReassignments) != 0 {
+				t.Errorf("reassigned = %v, failed = %v; want pr-1 reassigned", response.ReassignedPRs, response.FailedReassignments)
+			}
+			if response.Job.Status != models.JobCompleted {
+				t.Errorf("job status = %s, want %s", response.Job.Status, models.JobCompleted)
+			}
+			if user, _ := e.repos.Users.GetByID(ctx, "bob"); user.IsActive != tt.wantActive {
+				t.Errorf("bob active = %v, want %v", user.IsActive, tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestDeactivateUsersAtomicRollsBack(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t)
+	e.addTeam(t, "backend", "alice", "bob", "carol")
+	e.createPR(t, "pr-1", "alice")
+
+	// Nobody is left to replace bob.
+	response, err := e.deactivation.DeactivateUsersAtomic(ctx, "backend", []string{"bob"}, "admin")
+	if err != ErrReassignmentFailed {
+		t.Fatalf("DeactivateUsersAtomic error = %v, want %v", err, ErrReassignmentFailed)
+	}
+	if response.Job != nil {
+		t.Errorf("job = %+v, want none: it was rolled back", response.Job)
+	}
+	if len(response.Reassignments) != 1 {
+		t.Fatalf("reassignments = %+v, want one", response.Reassignments)
+	}
+	if r := response.Reassignments[0]; r.PullRequestID != "pr-1" || r.Status != models.JobItemFailed || r.Error == "" {
+		t.Errorf("reassignment = %+v, want pr-1 FAILED with a reason", r)
+	}
+
+	if user, _ := e.repos.Users.GetByID(ctx, "bob"); !user.IsActive {
+		t.Error("bob was deactivated")
+	}
+	if n, err := e.deactivation.ProcessDue(ctx); err != nil || n != 0 {
+		t.Errorf("ProcessDue = %d, %v; want no saved job items", n, err)
+	}
+	if got := e.notifier.count(models.WebhookUsersDeactivated); got != 0 {
+		t.Errorf("%s notifications = %d, want 0", models.WebhookUsersDeactivated, got)
+	}
+}
+
+func TestRetryJob(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t)
+	e.addTeam(t, "backend", "alice", "bob", "carol")
+	e.createPR(t, "pr-1", "alice")
+
+	// Nobody is left to replace bob, so the only item fails.
+	response, err := e.deactivation.DeactivateUsers(ctx, "backend", []string{"bob"}, "admin")
+	if err != nil {
+		t.Fatalf("DeactivateUsers: %v", err)
+	}
+	jobID := response.Job.JobID
+	if _, err := e.deactivation.ProcessDue(ctx); err != nil {
+		t.Fatalf("ProcessDue: %v", err)
+	}
+	if job, _ := e.deactivation.GetJob(ctx, jobID); job.Status != models.JobCompleted || job.Progress.Failed != 1 {
+		t.Fatalf("job = %+v, want COMPLETED with a FAILED item", job)
+	}
+
+	if _, err := e.deactivation.RetryJob(ctx, jobID+100); err != repository.ErrJobNotFound {
+		t.Errorf("RetryJob of an unknown job error = %v, want %v", err, repository.ErrJobNotFound)
+	}
+
+	err = e.repos.Users.CreateOrUpdate(ctx, &models.User{UserID: "dave", Username: "dave", TeamName: "backend", IsActive: true})
+	if err != nil {
+		t.Fatalf("CreateOrUpdate: %v", err)
+	}
+	job, err := e.deactivation.RetryJob(ctx, jobID)
+	if err != nil {
+		t.Fatalf("RetryJob: %v", err)
+	}
+	if item := job.Items[0]; job.Status != models.JobRunning || job.FinishedAt != nil || item.Status != models.JobItemPending || item.Attempts != 0 {
+		t.Fatalf("job = %+v, item = %+v; want the item requeued with a fresh budget", job, item)
+	}
+
+	if n, err := e.deactivation.ProcessDue(ctx); err != nil || n != 1 {
+		t.Fatalf("ProcessDue = %d, %v; want 1", n, err)
+	}
+	job, err = e.deactivation.GetJob(ctx, jobID)
+	if err != nil {
+		t.Fatalf("GetJob: %v", err)
+	}
+	if job.Status != models.JobCompleted || job.Items[0].ReplacedBy != "dave" {
+		t.Errorf("job = %+v, want bob replaced by dave", job)
+	}
+	if got := e.notifier.count(models.WebhookUsersDeactivated); got != 2 {
+		t.Errorf("%s notifications = %d, want one per completion", models.WebhookUsersDeactivated, got)
+	}
+	if _, err := e.deactivation.RetryJob(ctx, jobID); err != repository.ErrJobNotFound {
+		t.Errorf("RetryJob without failed items error = %v, want %v", err, repository.ErrJobNotFound)
+	}
+}
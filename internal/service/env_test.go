@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"pr-reviewer-service/internal/integration/gitlab"
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+	"pr-reviewer-service/internal/repository/memory"
+)
+
+const (
+	testGitHubSecret = "github-secret"
+	testGitLabToken  = "gitlab-token"
+)
+
+// testEnv wires the services to a fresh in-memory store, the same way
+// cmd/server does with -storage memory. Reviewers are picked round-robin in
+// user_id order, so assignments are predictable.
+type testEnv struct {
+	store    *memory.Store
+	repos    *repository.Repositories
+	notifier *recordingNotifier
+	metrics  *recordingMetrics
+	gitlab   *gitlab.FakeClient
+
+	mappingRepo repository.UserMappingRepository
+	syncRepo    repository.ReviewerSyncRepository
+
+	teams        *TeamService
+	prs          *PullRequestService
+	deactivation *DeactivationService
+	reactivation *ReactivationService
+	availability *AvailabilityService
+	integrations *IntegrationService
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	store := memory.NewStore()
+	repos := &repository.Repositories{
+		Users:        memory.NewUserRepository(store),
+		Teams:        memory.NewTeamRepository(store),
+		PullRequests: memory.NewPullRequestRepository(store),
+		Events:       memory.NewEventRepository(store),
+		Jobs:         memory.NewJobRepository(store),
+	}
+
+	selectors, err := NewSelectorRegistry(StrategyRoundRobin, nil, repos.Users)
+	if err != nil {
+		t.Fatalf("NewSelectorRegistry: %v", err)
+	}
+
+	e := &testEnv{
+		store:       store,
+		repos:       repos,
+		notifier:    &recordingNotifier{},
+		metrics:     &recordingMetrics{},
+		gitlab:      gitlab.NewFakeClient(),
+		mappingRepo: memory.NewUserMappingRepository(store),
+		syncRepo:    memory.NewReviewerSyncRepository(store),
+	}
+	syncer := NewReviewerSyncer(e.mappingRepo, e.syncRepo, e.gitlab)
+	notifier := Notifiers{e.notifier, syncer}
+
+	e.teams = NewTeamService(repos.Teams)
+	availabilityRepo := memory.NewAvailabilityRepository(store)
+	e.prs = NewPullRequestService(
+		repos.PullRequests, repos.Users, repos.Teams, repos.Events, availabilityRepo,
+		selectors, notifier, e.metrics, memory.NewTransactor(store),
+	)
+	e.deactivation = NewDeactivationService(
+		repos.Users, repos.Teams, repos.PullRequests, repos.Events, repos.Jobs,
+		e.prs, notifier, RetryPolicy{MaxAttempts: 1},
+	)
+	e.reactivation = NewReactivationService(repos.Users, repos.PullRequests, repos.Events, e.prs, notifier)
+	e.availability = NewAvailabilityService(availabilityRepo, repos.Users, repos.PullRequests, e.prs)
+	e.integrations = NewIntegrationService(
+		e.mappingRepo, e.syncRepo, repos.Users, e.prs, syncer, testGitHubSecret, testGitLabToken,
+	)
+	return e
+}
+
+// newReviewEnv has the backend team of alice, bob, carol and dave and pr-1 by
+// alice, reviewed by bob and carol.
+func newReviewEnv(t *testing.T) *testEnv {
+	t.Helper()
+	e := newTestEnv(t)
+	e.addTeam(t, "backend", "alice", "bob", "carol", "dave")
+	if pr := e.createPR(t, "pr-1", "alice"); !sameUserIDs(pr.AssignedReviewers, []string{"bob", "carol"}) {
+		t.Fatalf("reviewers = %v, want [bob carol]", pr.AssignedReviewers)
+	}
+	return e
+}
+
+// addTeam creates an active team whose members have the given user ids.
+func (e *testEnv) addTeam(t *testing.T, teamName string, userIDs ...string) {
+	t.Helper()
+	team := &models.Team{TeamName: teamName}
+	for _, userID := range userIDs {
+		team.Members = append(team.Members, models.TeamMember{UserID: userID, Username: userID, IsActive: true})
+	}
+	if err := e.teams.CreateTeam(context.Background(), team); err != nil {
+		t.Fatalf("CreateTeam(%s): %v", teamName, err)
+	}
+}
+
+// updateSettings changes the settings of a team, starting from its current ones.
+func (e *testEnv) updateSettings(t *testing.T, teamName string, change func(settings *models.TeamSettings)) {
+	t.Helper()
+	settings, err := e.teams.GetSettings(context.Background(), teamName)
+	if err != nil {
+		t.Fatalf("GetSettings(%s): %v", teamName, err)
+	}
+	change(settings)
+	if _, err := e.teams.UpdateSettings(context.Background(), settings); err != nil {
+		t.Fatalf("UpdateSettings(%s): %v", teamName, err)
+	}
+}
+
+// setCapacity limits how many open reviews the user takes.
+func (e *testEnv) setCapacity(t *testing.T, userID string, maxOpenReviews int) {
+	t.Helper()
+	if err := e.repos.Users.SetMaxOpenReviews(context.Background(), userID, &maxOpenReviews); err != nil {
+		t.Fatalf("SetMaxOpenReviews(%s): %v", userID, err)
+	}
+}
+
+func (e *testEnv) createPR(t *testing.T, prID, authorID string) *models.PullRequest {
+	t.Helper()
+	pr, err := e.prs.CreatePR(context.Background(), prID, prID, authorID, false, nil, "test")
+	if err != nil {
+		t.Fatalf("CreatePR(%s): %v", prID, err)
+	}
+	return pr
+}
+
+func (e *testEnv) getPR(t *testing.T, prID string) *models.PullRequest {
+	t.Helper()
+	pr, err := e.repos.PullRequests.GetByID(context.Background(), prID)
+	if err != nil {
+		t.Fatalf("GetByID(%s): %v", prID, err)
+	}
+	return pr
+}
+
+func (e *testEnv) mapLogin(t *testing.T, provider, login, userID string) {
+	t.Helper()
+	if _, err := e.integrations.SetUserMapping(context.Background(), provider, login, userID); err != nil {
+		t.Fatalf("SetUserMapping(%s, %s): %v", provider, login, err)
+	}
+}
+
+// recordingNotifier remembers the event types it was notified about and
+// fails every notification with err, when set.
+type recordingNotifier struct {
+	mu     sync.Mutex
+	events []string
+	err    error
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, eventType string, data interface{}) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.events = append(n.events, eventType)
+	return n.err
+}
+
+func (n *recordingNotifier) count(eventType string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	count := 0
+	for _, event := range n.events {
+		if event == eventType {
+			count++
+		}
+	}
+	return count
+}
+
+// recordingMetrics counts the reported outcomes by "operation/outcome" and
+// deactivation reassignment result.
+type recordingMetrics struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (m *recordingMetrics) AssignmentOutcome(operation, outcome string) {
+	m.add(operation + "/" + outcome)
+}
+
+func (m *recordingMetrics) DeactivationReassignment(result string) {
+	m.add("deactivation/" + result)
+}
+
+func (m *recordingMetrics) add(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counts == nil {
+		m.counts = make(map[string]int)
+	}
+	m.counts[key]++
+}
+
+func (m *recordingMetrics) count(key string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counts[key]
+}
+
+func sameUserIDs(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
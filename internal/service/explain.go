@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+// explainer collects an Explanation while reviewers are selected. A nil
+// explainer records nothing, so the selection code calls it unconditionally.
+// With preview set, stateful strategies do not advance their state.
+type explainer struct {
+	userRepo         repository.UserRepository
+	availabilityRepo repository.AvailabilityRepository
+	explanation      *models.Explanation
+	preview          bool
+	away             map[string]bool
+}
+
+func newExplainer(
+	userRepo repository.UserRepository,
+	availabilityRepo repository.AvailabilityRepository,
+	operation string,
+	preview bool,
+) *explainer {
+	return &explainer{
+		userRepo:         userRepo,
+		availabilityRepo: availabilityRepo,
+		explanation: &models.Explanation{
+			Operation: operation,
+			Pools:     make([]*models.CandidatePool, 0),
+			Picked:    make([]string, 0),
+		},
+		preview: preview,
+		away:    make(map[string]bool),
+	}
+}
+
+func (e *explainer) result() *models.Explanation {
+	if e == nil {
+		return nil
+	}
+	return e.explanation
+}
+
+func (e *explainer) subject(author *models.User, reviewerCount int) {
+	if e == nil {
+		return
+	}
+	e.explanation.AuthorID = author.UserID
+	e.explanation.TeamName = author.TeamName
+	e.explanation.ReviewerCount = reviewerCount
+}
+
+func (e *explainer) replacing(prID, oldUserID string) {
+	if e == nil {
+		return
+	}
+	e.explanation.PullRequestID = prID
+	e.explanation.ReplacedUserID = oldUserID
+}
+
+func (e *explainer) pool(source, teamName string, slots int) *models.CandidatePool {
+	if e == nil {
+		return nil
+	}
+	pool := &models.CandidatePool{
+		Source:     source,
+		TeamName:   teamName,
+		Slots:      slots,
+		Candidates: make([]string, 0),
+		Exclusions: make([]models.Exclusion, 0),
+		Picked:     make([]string, 0),
+	}
+	e.explanation.Pools = append(e.explanation.Pools, pool)
+	return pool
+}
+
+// reason tells why a team member is missing from the active members list.
+func (e *explainer) reason(user *models.User) string {
+	switch {
+	case user.UserID == e.explanation.ReplacedUserID:
+		return models.ExclusionReplaced
+	case user.UserID == e.explanation.AuthorID:
+		return models.ExclusionAuthor
+	case !user.IsActive:
+		return models.ExclusionInactive
+	case e.away[user.UserID]:
+		return models.ExclusionOnVacation
+	}
+	return models.ExclusionOther
+}
+
+// members records the members of teamName that are not in active.
+func (e *explainer) members(ctx context.Context, pool *models.CandidatePool, teamName string, active []*models.User) error {
+	if e == nil {
+		return nil
+	}
+	members, err := e.userRepo.GetUsersByTeam(ctx, teamName)
+	if err != nil {
+		return err
+	}
+
+	kept := make(map[string]bool, len(active))
+	for _, user := range active {
+		kept[user.UserID] = true
+	}
+	now := time.Now()
+	for _, member := range members {
+		if kept[member.UserID] || !member.IsActive {
+			continue
+		}
+		windows, err := e.availabilityRepo.GetByUser(ctx, member.UserID)
+		if err != nil {
+			return err
+		}
+		for _, a := range windows {
+			if a.Covers(now) {
+				e.away[member.UserID] = true
+			}
+		}
+	}
+
+	e.exclude(pool, members, active, "")
+	return nil
+}
+
+// exclude records users of before that are not in after.
+func (e *explainer) exclude(pool *models.CandidatePool, before, after []*models.User, reason string) {
+	if e == nil {
+		return
+	}
+	kept := make(map[string]bool, len(after))
+	for _, user := range after {
+		kept[user.UserID] = true
+	}
+	for _, user := range before {
+		if !kept[user.UserID] {
+			e.excludeUser(pool, user, reason)
+		}
+	}
+}
+
+// excludeUser records a single exclusion. An empty reason is derived from the
+// user; the author and the replaced reviewer are reported as such instead of
+// already_assigned. Every user is reported once per pool.
+func (e *explainer) excludeUser(pool *models.CandidatePool, user *models.User, reason string) {
+	if e == nil {
+		return
+	}
+	for _, exclusion := range pool.Exclusions {
+		if exclusion.UserID == user.UserID {
+			return
+		}
+	}
+
+	derived := e.reason(user)
+	if reason == "" || reason == models.ExclusionAlreadyAssigned &&
+		(derived == models.ExclusionAuthor || derived == models.ExclusionReplaced) {
+		reason = derived
+	}
+	pool.Exclusions = append(pool.Exclusions, models.Exclusion{UserID: user.UserID, Reason: reason})
+}
+
+// selectReviewers runs the strategy over candidates and records the pool.
+func (e *explainer) selectReviewers(
+	ctx context.Context,
+	pool *models.CandidatePool,
+	selector ReviewerSelector,
+	teamName string,
+	candidates []*models.User,
+	count int,
+) ([]*models.User, error) {
+	if e == nil {
+		return selector.Select(ctx, teamName, candidates, count)
+	}
+
+	pool.Strategy = selector.Name()
+	for _, candidate := range candidates {
+		pool.Candidates = append(pool.Candidates, candidate.UserID)
+	}
+	if scored, ok := selector.(ScoredSelector); ok {
+		scores, err := scored.Scores(ctx, teamName, candidates)
+		if err != nil {
+			return nil, err
+		}
+		pool.Scores = scores
+	}
+
+	var selected []*models.User
+	var err error
+	if previewer, ok := selector.(PreviewSelector); ok && e.preview {
+		selected, err = previewer.Preview(ctx, teamName, candidates, count)
+	} else {
+		selected, err = selector.Select(ctx, teamName, candidates, count)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reviewer := range selected {
+		pool.Picked = append(pool.Picked, reviewer.UserID)
+		e.explanation.Picked = append(e.explanation.Picked, reviewer.UserID)
+	}
+	return selected, nil
+}
+
+// isSelectionError reports whether err means no suitable reviewer was found,
+// as opposed to a missing entity or a storage failure.
+func isSelectionError(err error) bool {
+	return err == ErrNotEnoughReviewers || err == ErrAllAtCapacity || err == ErrNoCandidate
+}
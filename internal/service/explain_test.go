@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+func exclusionReasons(pool *models.CandidatePool) map[string]string {
+	reasons := make(map[string]string, len(pool.Exclusions))
+	for _, exclusion := range pool.Exclusions {
+		reasons[exclusion.UserID] = exclusion.Reason
+	}
+	return reasons
+}
+
+func TestExplainCreate(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t)
+	e.addTeam(t, "backend", "alice", "bob", "carol", "dave", "erin", "frank")
+	if err := e.repos.Users.SetIsActive(ctx, "bob", false); err != nil {
+		t.Fatalf("SetIsActive: %v", err)
+	}
+	startedAbsence(t, e, "carol", -time.Hour, time.Hour)
+	e.setCapacity(t, "dave", 0)
+
+	explanation, err := e.prs.ExplainCreate(ctx, "alice", nil)
+	if err != nil {
+		t.Fatalf("ExplainCreate: %v", err)
+	}
+	if len(explanation.Pools) != 1 || explanation.Pools[0].Source != models.PoolTeam {
+		t.Fatalf("pools = %+v, want the team pool only", explanation.Pools)
+	}
+	pool := explanation.Pools[0]
+
+	wantReasons := map[string]string{
+		"alice": models.ExclusionAuthor,
+		"bob":   models.ExclusionInactive,
+		"carol": models.ExclusionOnVacation,
+		"dave":  models.ExclusionAtCapacity,
+	}
+	if got := exclusionReasons(pool); !reflect.DeepEqual(got, wantReasons) {
+		t.Errorf("exclusions = %v, want %v", got, wantReasons)
+	}
+	if !sameUserIDs(pool.Candidates, []string{"erin", "frank"}) || !sameUserIDs(explanation.Picked, []string{"erin", "frank"}) {
+		t.Errorf("candidates = %v, picked = %v, want erin and frank", pool.Candidates, explanation.Picked)
+	}
+
+	// The explanation matches what CreatePR then does.
+	if pr := e.createPR(t, "pr-1", "alice"); !sameUserIDs(pr.AssignedReviewers, explanation.Picked) {
+		t.Errorf("reviewers = %v, want the explained %v", pr.AssignedReviewers, explanation.Picked)
+	}
+}
+
+func TestExplainReassign(t *testing.T) {
+	ctx := context.Background()
+	e := newReviewEnv(t)
+
+	explanation, err := e.prs.ExplainReassign(ctx, "pr-1", "bob")
+	if err != nil {
+		t.Fatalf("ExplainReassign: %v", err)
+	}
+	wantReasons := map[string]string{
+		"alice": models.ExclusionAuthor,
+		"bob":   models.ExclusionReplaced,
+		"carol": models.ExclusionAlreadyAssigned,
+	}
+	if got := exclusionReasons(explanation.Pools[0]); !reflect.DeepEqual(got, wantReasons) {
+		t.Errorf("exclusions = %v, want %v", got, wantReasons)
+	}
+	if !sameUserIDs(explanation.Picked, []string{"dave"}) || explanation.Error != "" {
+		t.Errorf("picked = %v, error = %q, want dave", explanation.Picked, explanation.Error)
+	}
+
+	startedAbsence(t, e, "dave", -time.Hour, time.Hour)
+	explanation, err = e.prs.ExplainReassign(ctx, "pr-1", "bob")
+	if err != nil {
+		t.Fatalf("ExplainReassign: %v", err)
+	}
+	if got := exclusionReasons(explanation.Pools[0])["dave"]; got != models.ExclusionOnVacation {
+		t.Errorf("dave exclusion = %q, want %q", got, models.ExclusionOnVacation)
+	}
+	if explanation.Error != ErrNoCandidate.Error() {
+		t.Errorf("error = %q, want %q", explanation.Error, ErrNoCandidate)
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+// Heartbeat tracks the passes of a background worker, so that readiness can
+// tell a worker stuck in a pass from one waiting for its next tick.
+type Heartbeat struct {
+	name string
+
+	mu             sync.Mutex
+	running        bool
+	inPass         bool
+	lastStartedAt  time.Time
+	lastFinishedAt time.Time
+}
+
+func newHeartbeat(name string) *Heartbeat {
+	return &Heartbeat{name: name}
+}
+
+func (h *Heartbeat) Name() string {
+	return h.name
+}
+
+// Start marks the worker as running. Calling it before the goroutine of the
+// worker is launched keeps readiness from reporting the worker stopped until
+// the goroutine gets to its loop.
+func (h *Heartbeat) Start() {
+	h.mu.Lock()
+	h.running = true
+	h.mu.Unlock()
+}
+
+// run marks the worker loop as running until the returned func is called.
+func (h *Heartbeat) run() func() {
+	h.Start()
+
+	return func() {
+		h.mu.Lock()
+		h.running = false
+		h.mu.Unlock()
+	}
+}
+
+// pass marks a pass as started until the returned func is called.
+func (h *Heartbeat) pass() func() {
+	h.mu.Lock()
+	h.inPass = true
+	h.lastStartedAt = time.Now()
+	h.mu.Unlock()
+
+	return func() {
+		h.mu.Lock()
+		h.inPass = false
+		h.lastFinishedAt = time.Now()
+		h.mu.Unlock()
+	}
+}
+
+// status reports the state of the worker. A pass running for longer than
+// stuckAfter makes it stuck.
+func (h *Heartbeat) status(now time.Time, stuckAfter time.Duration) *models.WorkerStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	status := &models.WorkerStatus{Name: h.name, State: models.WorkerIdle}
+	if !h.lastStartedAt.IsZero() {
+		startedAt := h.lastStartedAt
+		status.LastStartedAt = &startedAt
+	}
+	if !h.lastFinishedAt.IsZero() {
+		finishedAt := h.lastFinishedAt
+		status.LastFinishedAt = &finishedAt
+	}
+
+	switch {
+	case !h.running:
+		status.State = models.WorkerStopped
+	case h.inPass:
+		runningFor := now.Sub(h.lastStartedAt)
+		status.RunningForSeconds = int64(runningFor / time.Second)
+		status.State = models.WorkerRunning
+		if runningFor > stuckAfter {
+			status.State = models.WorkerStuck
+		}
+	}
+	return status
+}
+
+type HealthService struct {
+	statusRepo repository.StatusRepository
+	workers    []*Heartbeat
+	timeout    time.Duration
+	stuckAfter time.Duration
+}
+
+// NewHealthService returns a service checking the storage within timeout and
+// reporting workers whose pass takes longer than stuckAfter as stuck.
+func NewHealthService(
+	statusRepo repository.StatusRepository,
+	workers []*Heartbeat,
+	timeout time.Duration,
+	stuckAfter time.Duration,
+) *HealthService {
+	return &HealthService{
+		statusRepo: statusRepo,
+		workers:    workers,
+		timeout:    timeout,
+		stuckAfter: stuckAfter,
+	}
+}
+
+// Readiness checks that the database answers, that its schema has at least
+// the expected migration version and that no background worker is stuck or
+// stopped.
+func (s *HealthService) Readiness(ctx context.Context) *models.Readiness {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	readiness := &models.Readiness{
+		Status: models.CheckOK,
+		Checks: models.ReadinessChecks{
+			Database:   s.checkDatabase(ctx),
+			Migrations: s.checkMigrations(ctx),
+			Workers:    s.checkWorkers(),
+		},
+	}
+	for _, status := range []models.CheckStatus{
+		readiness.Checks.Database.Status,
+		readiness.Checks.Migrations.Status,
+		readiness.Checks.Workers.Status,
+	} {
+		if status != models.CheckOK {
+			readiness.Status = models.CheckFailed
+		}
+	}
+	return readiness
+}
+
+func (s *HealthService) checkDatabase(ctx context.Context) *models.DatabaseCheck {
+	start := time.Now()
+	err := s.statusRepo.Ping(ctx)
+
+	check := &models.DatabaseCheck{
+		Status:     models.CheckOK,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		check.Status = models.CheckFailed
+		check.Error = err.Error()
+	}
+	return check
+}
+
+func (s *HealthService) checkMigrations(ctx context.Context) *models.MigrationsCheck {
+	check := &models.MigrationsCheck{
+		Status:          models.CheckOK,
+		ExpectedVersion: repository.SchemaVersion,
+	}
+
+	version, dirty, err := s.statusRepo.SchemaVersion(ctx)
+	switch {
+	case err != nil:
+		check.Error = err.Error()
+	case dirty:
+		check.Error = fmt.Sprintf("migration %d failed halfway", version)
+	case version < repository.SchemaVersion:
+		// A newer schema is fine: during a rolling deploy the new release
+		// migrates while the old pods still serve, and migrations are additive.
+		check.Error = fmt.Sprintf("schema version is %d, expected at least %d", version, repository.SchemaVersion)
+	}
+	check.Version = version
+	check.Dirty = dirty
+	if check.Error != "" {
+		check.Status = models.CheckFailed
+	}
+	return check
+}
+
+func (s *HealthService) checkWorkers() *models.WorkersCheck {
+	check := &models.WorkersCheck{
+		Status:  models.CheckOK,
+		Workers: make([]*models.WorkerStatus, 0, len(s.workers)),
+	}
+
+	now := time.Now()
+	for _, worker := range s.workers {
+		status := worker.status(now, s.stuckAfter)
+		if status.State == models.WorkerStuck || status.State == models.WorkerStopped {
+			check.Status = models.CheckFailed
+		}
+		check.Workers = append(check.Workers, status)
+	}
+	return check
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+type fakeStatusRepository struct {
+	version int64
+	dirty   bool
+	err     error
+}
+
+func (r *fakeStatusRepository) Ping(ctx context.Context) error {
+	return r.err
+}
+
+func (r *fakeStatusRepository) SchemaVersion(ctx context.Context) (int64, bool, error) {
+	return r.version, r.dirty, r.err
+}
+
+func TestReadinessMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		repo    *fakeStatusRepository
+		wantErr string
+	}{
+		{"expected version", &fakeStatusRepository{version: repository.SchemaVersion}, ""},
+		// An old pod keeps serving while a newer release has migrated.
+		{"newer version", &fakeStatusRepository{version: repository.SchemaVersion + 1}, ""},
+		{"older version", &fakeStatusRepository{version: repository.SchemaVersion - 1}, fmt.Sprintf("schema version is %d, expected at least %d", repository.SchemaVersion-1, repository.SchemaVersion)},
+		{"dirty", &fakeStatusRepository{version: repository.SchemaVersion + 1, dirty: true}, fmt.Sprintf("migration %d failed halfway", repository.SchemaVersion+1)},
+		{"unavailable", &fakeStatusRepository{err: errors.New("connection refused")}, "connection refused"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewHealthService(tt.repo, nil, time.Second, time.Minute)
+			check := s.Readiness(context.Background()).Checks.Migrations
+
+			wantStatus := models.CheckOK
+			if tt.wantErr != "" {
+				wantStatus = models.CheckFailed
+			}
+			if check.Status != wantStatus || check.Error != tt.wantErr {
+				t.Errorf("migrations check = %s %q, want %s %q", check.Status, check.Error, wantStatus, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadinessWorkers(t *testing.T) {
+	started := newHeartbeat("started")
+	started.Start()
+
+	stuck := newHeartbeat("stuck")
+	stuck.Start()
+	stuck.pass()
+	stuck.lastStartedAt = time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		heartbeat  *Heartbeat
+		wantState  models.WorkerState
+		wantStatus models.CheckStatus
+	}{
+		// Started before its goroutine reached the loop.
+		{"started", started, models.WorkerIdle, models.CheckOK},
+		{"not started", newHeartbeat("not started"), models.WorkerStopped, models.CheckFailed},
+		{"stuck", stuck, models.WorkerStuck, models.CheckFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeStatusRepository{version: repository.SchemaVersion}
+			s := NewHealthService(repo, []*Heartbeat{tt.heartbeat}, time.Second, time.Minute)
+			check := s.Readiness(context.Background()).Checks.Workers
+			if check.Status != tt.wantStatus || check.Workers[0].State != tt.wantState {
+				t.Errorf("workers check = %s with %s, want %s with %s",
+					check.Status, check.Workers[0].State, tt.wantStatus, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestHeartbeatStoppedWhenRunReturns(t *testing.T) {
+	e := newTestEnv(t)
+	heartbeat := e.deactivation.Heartbeat()
+	heartbeat.Start()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	e.deactivation.Run(ctx, time.Hour)
+
+	if state := heartbeat.status(time.Now(), time.Minute).State; state != models.WorkerStopped {
+		t.Errorf("state after Run returned = %s, want %s", state, models.WorkerStopped)
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"pr-reviewer-service/internal/logging"
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+type HistoryService struct {
+	eventRepo repository.EventRepository
+	prRepo    repository.PullRequestRepository
+	userRepo  repository.UserRepository
+}
+
+func NewHistoryService(
+	eventRepo repository.EventRepository,
+	prRepo repository.PullRequestRepository,
+	userRepo repository.UserRepository,
+) *HistoryService {
+	return &HistoryService{
+		eventRepo: eventRepo,
+		prRepo:    prRepo,
+		userRepo:  userRepo,
+	}
+}
+
+func (s *HistoryService) GetPRHistory(ctx context.Context, prID string) ([]*models.Event, error) {
+	ctx = logging.With(ctx, "pull_request_id", prID)
+	_, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	return s.eventRepo.GetByPullRequest(ctx, prID)
+}
+
+// GetUserHistory returns events where the user is the subject, the replaced
+// reviewer or the actor.
+func (s *HistoryService) GetUserHistory(ctx context.Context, userID string) ([]*models.Event, error) {
+	ctx = logging.With(ctx, "user_id", userID)
+	_, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.eventRepo.GetByUser(ctx, userID)
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"pr-reviewer-service/internal/integration/github"
+	"pr-reviewer-service/internal/integration/gitlab"
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrInvalidToken     = errors.New("invalid webhook token")
+	ErrInvalidPayload   = errors.New("invalid webhook payload")
+	ErrUnknownProvider  = errors.New("unknown integration provider")
+	ErrLoginRequired    = errors.New("login is required")
+	ErrLoginNotMapped   = errors.New("login is not mapped to a user")
+
+	ErrGitLabNotConfigured = errors.New("gitlab client is not configured")
+)
+
+type IntegrationService struct {
+	mappingRepo  repository.UserMappingRepository
+	syncRepo     repository.ReviewerSyncRepository
+	userRepo     repository.UserRepository
+	prService    *PullRequestService
+	syncer       *ReviewerSyncer
+	githubSecret string
+	gitlabToken  string
+}
+
+func NewIntegrationService(
+	mappingRepo repository.UserMappingRepository,
+	syncRepo repository.ReviewerSyncRepository,
+	userRepo repository.UserRepository,
+	prService *PullRequestService,
+	syncer *ReviewerSyncer,
+	githubSecret string,
+	gitlabToken string,
+) *IntegrationService {
+	return &IntegrationService{
+		mappingRepo:  mappingRepo,
+		syncRepo:     syncRepo,
+		userRepo:     userRepo,
+		prService:    prService,
+		syncer:       syncer,
+		githubSecret: githubSecret,
+		gitlabToken:  gitlabToken,
+	}
+}
+
+func isProvider(provider string) bool {
+	return provider == models.ProviderGitHub || provider == models.ProviderGitLab
+}
+
+func (s *IntegrationService) SetUserMapping(ctx context.Context, provider, login, userID string) (*models.UserMapping, error) {
+	if !isProvider(provider) {
+		return nil, ErrUnknownProvider
+	}
+	if login == "" {
+		return nil, ErrLoginRequired
+	}
+	_, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := &models.UserMapping{Provider: provider, Login: login, UserID: userID}
+	err = s.mappingRepo.Set(ctx, mapping)
+	if err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+func (s *IntegrationService) GetUserMappings(ctx context.Context, provider string) ([]*models.UserMapping, error) {
+	if !isProvider(provider) {
+		return nil, ErrUnknownProvider
+	}
+	return s.mappingRepo.GetByProvider(ctx, provider)
+}
+
+func (s *IntegrationService) DeleteUserMapping(ctx context.Context, provider, login string) error {
+	if !isProvider(provider) {
+		return ErrUnknownProvider
+	}
+	return s.mappingRepo.Delete(ctx, provider, login)
+}
+
+// HandleGitHubWebhook applies a GitHub delivery to the PR it refers to.
+// Events and actions the service does not track are acknowledged and ignored.
+func (s *IntegrationService) HandleGitHubWebhook(ctx context.Context, eventType, signature string, body []byte) (*models.SyncResult, error) {
+	if err := github.VerifySignature(s.githubSecret, body, signature); err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	result := &models.SyncResult{
+		Provider: models.ProviderGitHub,
+		Event:    eventType,
+		Result:   models.SyncIgnored,
+	}
+	if eventType != github.EventPullRequest {
+		return result, nil
+	}
+
+	event, err := github.ParsePullRequestEvent(body)
+	if err != nil {
+		return nil, ErrInvalidPayload
+	}
+	result.Action = event.Action
+	result.PullRequestID = event.PullRequestID()
+	actor := models.ProviderGitHub + ":" + event.Sender.Login
+
+	var pr *models.PullRequest
+	switch event.Action {
+	case github.ActionOpened:
+		authorID, err := s.resolveUser(ctx, models.ProviderGitHub, event.PullRequest.User.Login)
+		if err != nil {
+			return nil, err
+		}
+		pr, err = s.prService.CreatePR(ctx, result.PullRequestID, event.PullRequest.Title, authorID, event.PullRequest.Draft, nil, actor)
+		if err == repository.ErrPRExists {
+			// GitHub redelivers events, the PR was created by an earlier delivery.
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Result = models.SyncCreated
+	case github.ActionClosed:
+		if event.PullRequest.Merged {
+			pr, err = s.prService.RecordExternalMerge(ctx, result.PullRequestID, actor)
+			result.Result = models.SyncMerged
+		} else {
+			pr, err = s.prService.ClosePR(ctx, result.PullRequestID, actor)
+			result.Result = models.SyncClosed
+		}
+	case github.ActionReopened:
+		pr, err = s.prService.ReopenPR(ctx, result.PullRequestID, actor)
+		result.Result = models.SyncReopened
+	case github.ActionReadyForReview:
+		pr, err = s.prService.MarkReady(ctx, result.PullRequestID, actor)
+		result.Result = models.SyncReady
+	default:
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.PR = pr
+	return result, nil
+}
+
+// HandleGitLabWebhook applies a Merge Request Hook to the PR it refers to.
+// When the operation assigns reviewers, they are recorded and pushed back to
+// the merge request. Later replacements are pushed by the ReviewerSyncer.
+func (s *IntegrationService) HandleGitLabWebhook(ctx context.Context, eventType, token string, body []byte) (*models.SyncResult, error) {
+	if err := gitlab.VerifyToken(s.gitlabToken, token); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	result := &models.SyncResult{
+		Provider: models.ProviderGitLab,
+		Event:    eventType,
+		Result:   models.SyncIgnored,
+	}
+	if eventType != gitlab.EventMergeRequest {
+		return result, nil
+	}
+
+	event, err := gitlab.ParseMergeRequestEvent(body)
+	if err != nil {
+		return nil, ErrInvalidPayload
+	}
+	result.Action = event.ObjectAttributes.Action
+	result.PullRequestID = event.PullRequestID()
+	actor := models.ProviderGitLab + ":" + event.User.Username
+
+	var pr *models.PullRequest
+	switch event.ObjectAttributes.Action {
+	case gitlab.ActionOpen:
+		authorID, err := s.resolveUser(ctx, models.ProviderGitLab, event.User.Username)
+		if err != nil {
+			return nil, err
+		}
+		pr, err = s.prService.CreatePR(ctx, result.PullRequestID, event.ObjectAttributes.Title, authorID, event.ObjectAttributes.Draft, nil, actor)
+		if err == repository.ErrPRExists {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Result = models.SyncCreated
+	case gitlab.ActionReopen:
+		pr, err = s.prService.ReopenPR(ctx, result.PullRequestID, actor)
+		result.Result = models.SyncReopened
+	case gitlab.ActionClose:
+		pr, err = s.prService.ClosePR(ctx, result.PullRequestID, actor)
+		result.Result = models.SyncClosed
+	case gitlab.ActionMerge:
+		pr, err = s.prService.RecordExternalMerge(ctx, result.PullRequestID, actor)
+		result.Result = models.SyncMerged
+	case gitlab.ActionUpdate:
+		// Only the draft -> ready toggle matters, the service has no way back
+		// from OPEN to DRAFT.
+		if !event.MarkedReady() {
+			return result, nil
+		}
+		pr, err = s.prService.MarkReady(ctx, result.PullRequestID, actor)
+		result.Result = models.SyncReady
+	default:
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.PR = pr
+	if pr.Status == models.StatusOpen && len(pr.AssignedReviewers) > 0 {
+		result.ReviewerSync, err = s.recordReviewers(ctx, event, pr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (s *IntegrationService) recordReviewers(ctx context.Context, event *gitlab.MergeRequestEvent, pr *models.PullRequest) (*models.ReviewerSync, error) {
+	sync := &models.ReviewerSync{
+		Provider:        models.ProviderGitLab,
+		PullRequestID:   pr.PullRequestID,
+		ProjectID:       event.Project.ID,
+		MergeRequestIID: event.ObjectAttributes.IID,
+		Reviewers:       pr.AssignedReviewers,
+	}
+	err := s.syncer.Record(ctx, sync)
+	if err != nil {
+		return nil, err
+	}
+	return sync, nil
+}
+
+func (s *IntegrationService) GetReviewerSyncs(ctx context.Context, prID string) ([]*models.ReviewerSync, error) {
+	return s.syncRepo.GetByPullRequest(ctx, prID)
+}
+
+// PushReviewerSync pushes a recorded sync again, e.g. after a GitLab outage.
+func (s *IntegrationService) PushReviewerSync(ctx context.Context, syncID int64) (*models.ReviewerSync, error) {
+	if s.syncer.client == nil {
+		return nil, ErrGitLabNotConfigured
+	}
+	sync, err := s.syncRepo.GetByID(ctx, syncID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.syncer.Push(ctx, sync)
+	if err != nil {
+		return nil, err
+	}
+	return sync, nil
+}
+
+func (s *IntegrationService) resolveUser(ctx context.Context, provider, login string) (string, error) {
+	mapping, err := s.mappingRepo.Get(ctx, provider, login)
+	if err == repository.ErrMappingNotFound {
+		return "", ErrLoginNotMapped
+	}
+	if err != nil {
+		return "", err
+	}
+	return mapping.UserID, nil
+}
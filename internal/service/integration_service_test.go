@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"pr-reviewer-service/internal/integration/github"
+	"pr-reviewer-service/internal/integration/gitlab"
+	"pr-reviewer-service/internal/models"
+)
+
+// fixture reads a recorded webhook delivery from testdata.
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("fixture: %v", err)
+	}
+	return body
+}
+
+func signGitHub(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newGitHubEnv maps the logins used in the GitHub fixtures onto the members
+// of the backend team.
+func newGitHubEnv(t *testing.T) *testEnv {
+	t.Helper()
+	e := newTestEnv(t)
+	e.addTeam(t, "backend", "alice", "bob", "carol", "dave")
+	e.mapLogin(t, models.ProviderGitHub, "alice-gh", "alice")
+	e.mapLogin(t, models.ProviderGitHub, "bob-gh", "bob")
+	return e
+}
+
+func deliverGitHub(e *testEnv, eventType string, body []byte) (*models.SyncResult, error) {
+	return e.integrations.HandleGitHubWebhook(context.Background(), eventType, signGitHub(testGitHubSecret, body), body)
+}
+
+func TestGitHubWebhookSignature(t *testing.T) {
+	body := fixture(t, "github/pull_request_opened.json")
+	tests := []struct {
+		name      string
+		signature string
+		wantErr   error
+	}{
+		{"valid", signGitHub(testGitHubSecret, body), nil},
+		{"other secret", signGitHub("other", body), ErrInvalidSignature},
+		{"other body", signGitHub(testGitHubSecret, append([]byte(" "), body...)), ErrInvalidSignature},
+		{"sha1 header", "sha1=" + signGitHub(testGitHubSecret, body)[len("sha256="):], ErrInvalidSignature},
+		{"not hex", "sha256=zz", ErrInvalidSignature},
+		{"missing", "", ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newGitHubEnv(t)
+			_, err := e.integrations.HandleGitHubWebhook(context.Background(), github.EventPullRequest, tt.signature, body)
+			if err != tt.wantErr {
+				t.Fatalf("HandleGitHubWebhook error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGitHubWebhookRejectsEverythingWithoutSecret(t *testing.T) {
+	e := newGitHubEnv(t)
+	e.integrations.githubSecret = ""
+	body := fixture(t, "github/ping.json")
+	_, err := e.integrations.HandleGitHubWebhook(context.Background(), github.EventPing, signGitHub("", body), body)
+	if err != ErrInvalidSignature {
+		t.Fatalf("HandleGitHubWebhook error = %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+// TestGitHubWebhookActions replays a recorded PR lifecycle; every delivery
+// builds on the state left by the previous ones.
+func TestGitHubWebhookActions(t *testing.T) {
+	e := newGitHubEnv(t)
+	steps := []struct {
+		fixture    string
+		eventType  string
+		wantResult string
+		wantPR     string
+		wantStatus models.PullRequestStatus
+	}{
+		{"ping.json", github.EventPing, models.SyncIgnored, "", ""},
+		{"pull_request_opened.json", github.EventPullRequest, models.SyncCreated, "octo-org/api#42", models.StatusOpen},
+		{"pull_request_opened.json", github.EventPullRequest, models.SyncIgnored, "octo-org/api#42", models.StatusOpen},
+		{"pull_request_labeled.json", github.EventPullRequest, models.SyncIgnored, "octo-org/api#42", models.StatusOpen},
+		{"pull_request_closed.json", github.EventPullRequest, models.SyncClosed, "octo-org/api#42", models.StatusClosed},
+		{"pull_request_reopened.json", github.EventPullRequest, models.SyncReopened, "octo-org/api#42", models.StatusOpen},
+		{"pull_request_closed_merged.json", github.EventPullRequest, models.SyncMerged, "octo-org/api#42", models.StatusMerged},
+		{"pull_request_opened_draft.json", github.EventPullRequest, models.SyncCreated, "octo-org/api#43", models.StatusDraft},
+		{"pull_request_ready_for_review.json", github.EventPullRequest, models.SyncReady, "octo-org/api#43", models.StatusOpen},
+	}
+	for _, step := range steps {
+		result, err := deliverGitHub(e, step.eventType, fixture(t, "github/"+step.fixture))
+		if err != nil {
+			t.Fatalf("%s: %v", step.fixture, err)
+		}
+		if result.Result != step.wantResult || result.PullRequestID != step.wantPR {
+			t.Fatalf("%s: result = %s for %q, want %s for %q",
+				step.fixture, result.Result, result.PullRequestID, step.wantResult, step.wantPR)
+		}
+		if step.wantPR == "" {
+			continue
+		}
+		if pr := e.getPR(t, step.wantPR); pr.Status != step.wantStatus {
+			t.Fatalf("%s: status = %s, want %s", step.fixture, pr.Status, step.wantStatus)
+		}
+	}
+
+	pr := e.getPR(t, "octo-org/api#42")
+	if pr.AuthorID != "alice" || len(pr.AssignedReviewers) != models.DefaultReviewerCount {
+		t.Errorf("PR = %+v, want author alice with %d reviewers", pr, models.DefaultReviewerCount)
+	}
+	events, err := e.repos.Events.GetByPullRequest(context.Background(), pr.PullRequestID)
+	if err != nil {
+		t.Fatalf("GetByPullRequest: %v", err)
+	}
+	if last := events[len(events)-1]; last.EventType != models.EventMerged || last.Actor != "github:bob-gh" {
+		t.Errorf("last event = %+v, want merge by github:bob-gh", last)
+	}
+}
+
+// A merge on GitHub is a fact: the PR is merged even when the approvals the
+// team requires were never recorded here.
+func TestGitHubMergeSkipsApprovalGate(t *testing.T) {
+	e := newGitHubEnv(t)
+	e.updateSettings(t, "backend", func(settings *models.TeamSettings) {
+		settings.RequiredApprovals = 2
+	})
+
+	if _, err := deliverGitHub(e, github.EventPullRequest, fixture(t, "github/pull_request_opened.json")); err != nil {
+		t.Fatalf("opened: %v", err)
+	}
+	if _, err := e.prs.MergePR(context.Background(), "octo-org/api#42", "test"); err != ErrNotApproved {
+		t.Fatalf("MergePR error = %v, want %v", err, ErrNotApproved)
+	}
+
+	result, err := deliverGitHub(e, github.EventPullRequest, fixture(t, "github/pull_request_closed_merged.json"))
+	if err != nil {
+		t.Fatalf("closed merged: %v", err)
+	}
+	if result.Result != models.SyncMerged || result.PR.Status != models.StatusMerged {
+		t.Errorf("result = %s, status = %s; want merged", result.Result, result.PR.Status)
+	}
+	if got := e.notifier.count(models.WebhookPRMerged); got != 1 {
+		t.Errorf("%s notifications = %d, want 1", models.WebhookPRMerged, got)
+	}
+}
+
+func TestGitHubWebhookUnmappedAuthor(t *testing.T) {
+	e := newTestEnv(t)
+	e.addTeam(t, "backend", "alice", "bob")
+
+	_, err := deliverGitHub(e, github.EventPullRequest, fixture(t, "github/pull_request_opened.json"))
+	if err != ErrLoginNotMapped {
+		t.Fatalf("HandleGitHubWebhook error = %v, want %v", err, ErrLoginNotMapped)
+	}
+}
+
+func TestGitHubWebhookInvalidPayload(t *testing.T) {
+	e := newGitHubEnv(t)
+	for _, body := range []string{`{`, `{"action":"opened","pull_request":{"number":1}}`} {
+		if _, err := deliverGitHub(e, github.EventPullRequest, []byte(body)); err != ErrInvalidPayload {
+			t.Errorf("HandleGitHubWebhook(%s) error = %v, want %v", body, err, ErrInvalidPayload)
+		}
+	}
+}
+
+// newGitLabEnv maps the logins used in the GitLab fixtures onto the members of
+// the platform team. dave has no GitLab login.
+func newGitLabEnv(t *testing.T) *testEnv {
+	t.Helper()
+	e := newTestEnv(t)
+	e.addTeam(t, "platform", "alice", "bob", "carol", "dave")
+	e.mapLogin(t, models.ProviderGitLab, "alice-gl", "alice")
+	e.mapLogin(t, models.ProviderGitLab, "bob-gl", "bob")
+	e.mapLogin(t, models.ProviderGitLab, "carol-gl", "carol")
+	return e
+}
+
+func deliverGitLab(e *testEnv, body []byte) (*models.SyncResult, error) {
+	return e.integrations.HandleGitLabWebhook(context.Background(), gitlab.EventMergeRequest, testGitLabToken, body)
+}
+
+func TestGitLabWebhookToken(t *testing.T) {
+	body := fixture(t, "gitlab/mr_open.json")
+	tests := []struct {
+		name       string
+		configured string
+		token      string
+		wantErr    error
+	}{
+		{"valid", testGitLabToken, testGitLabToken, nil},
+		{"other token", testGitLabToken, "other", ErrInvalidToken},
+		{"missing", testGitLabToken, "", ErrInvalidToken},
+		{"not configured", "", "", ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newGitLabEnv(t)
+			e.integrations.gitlabToken = tt.configured
+			_, err := e.integrations.HandleGitLabWebhook(context.Background(), gitlab.EventMergeRequest, tt.token, body)
+			if err != tt.wantErr {
+				t.Fatalf("HandleGitLabWebhook error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestGitLabWebhookActions replays a recorded merge request lifecycle and
+// checks that the assigned reviewers are pushed back through the fake client.
+func TestGitLabWebhookActions(t *testing.T) {
+	e := newGitLabEnv(t)
+	e.updateSettings(t, "platform", func(settings *models.TeamSettings) {
+		settings.RequiredApprovals = 2
+	})
+	logins := map[string]string{"alice": "alice-gl", "bob": "bob-gl", "carol": "carol-gl"}
+
+	steps := []struct {
+		fixture    string
+		wantResult string
+		wantPR     string
+		wantStatus models.PullRequestStatus
+		wantPush   bool
+	}{
+		{"mr_open.json", models.SyncCreated, "platform/api!7", models.StatusOpen, true},
+		{"mr_open.json", models.SyncIgnored, "platform/api!7", models.StatusOpen, false},
+		{"mr_update_title.json", models.SyncIgnored, "platform/api!7", models.StatusOpen, false},
+		{"mr_close.json", models.SyncClosed, "platform/api!7", models.StatusClosed, false},
+		{"mr_reopen.json", models.SyncReopened, "platform/api!7", models.StatusOpen, true},
+		// Merged on GitLab without the two approvals the team requires.
+		{"mr_merge.json", models.SyncMerged, "platform/api!7", models.StatusMerged, false},
+		{"mr_open_draft.json", models.SyncCreated, "platform/api!8", models.StatusDraft, false},
+		{"mr_update_ready.json", models.SyncReady, "platform/api!8", models.StatusOpen, true},
+	}
+	for _, step := range steps {
+		calls := len(e.gitlab.Calls())
+		result, err := deliverGitLab(e, fixture(t, "gitlab/"+step.fixture))
+		if err != nil {
+			t.Fatalf("%s: %v", step.fixture, err)
+		}
+		if result.Result != step.wantResult || result.PullRequestID != step.wantPR {
+			t.Fatalf("%s: result = %s for %q, want %s for %q",
+				step.fixture, result.Result, result.PullRequestID, step.wantResult, step.wantPR)
+		}
+		pr := e.getPR(t, step.wantPR)
+		if pr.Status != step.wantStatus {
+			t.Fatalf("%s: status = %s, want %s", step.fixture, pr.Status, step.wantStatus)
+		}
+
+		newCalls := e.gitlab.Calls()[calls:]
+		if !step.wantPush {
+			if len(newCalls) != 0 || result.ReviewerSync != nil {
+				t.Fatalf("%s: pushed %+v, want nothing", step.fixture, newCalls)
+			}
+			continue
+		}
+		if result.ReviewerSync == nil || result.ReviewerSync.Status != models.SyncPushed {
+			t.Fatalf("%s: reviewer sync = %+v, want PUSHED", step.fixture, result.ReviewerSync)
+		}
+		// dave has no GitLab login, so the push leaves them out.
+		wantUsernames := make([]string, 0, len(pr.AssignedReviewers))
+		for _, reviewerID := range pr.AssignedReviewers {
+			if login, ok := logins[reviewerID]; ok {
+				wantUsernames = append(wantUsernames, login)
+			}
+		}
+		if len(newCalls) != 1 || newCalls[0].ProjectID != 15 || !sameUserIDs(newCalls[0].Usernames, wantUsernames) {
+			t.Fatalf("%s: pushed %+v, want %v to project 15", step.fixture, newCalls, wantUsernames)
+		}
+	}
+}
+
+func TestGitLabUnmappedReviewerIsReported(t *testing.T) {
+	e := newTestEnv(t)
+	e.addTeam(t, "platform", "alice", "bob", "dave")
+	e.mapLogin(t, models.ProviderGitLab, "alice-gl", "alice")
+	e.mapLogin(t, models.ProviderGitLab, "bob-gl", "bob")
+
+	result, err := deliverGitLab(e, fixture(t, "gitlab/mr_open.json"))
+	if err != nil {
+		t.Fatalf("HandleGitLabWebhook: %v", err)
+	}
+	sync := result.ReviewerSync
+	if sync.Status != models.SyncPushed || sync.Error != "reviewers without gitlab login: dave" {
+		t.Errorf("reviewer sync = %+v, want PUSHED with dave reported", sync)
+	}
+	if calls := e.gitlab.Calls(); len(calls) != 1 || !sameUserIDs(calls[0].Usernames, []string{"bob-gl"}) {
+		t.Errorf("pushed %+v, want only bob-gl", calls)
+	}
+}
+
+func TestGitLabPushFailureIsRecorded(t *testing.T) {
+	ctx := context.Background()
+	e := newGitLabEnv(t)
+	e.gitlab.Err = errors.New("gitlab is down")
+
+	result, err := deliverGitLab(e, fixture(t, "gitlab/mr_open.json"))
+	if err != nil {
+		t.Fatalf("a failed push must not fail the webhook: %v", err)
+	}
+	if result.ReviewerSync.Status != models.SyncFailed || result.ReviewerSync.Error != "gitlab is down" {
+		t.Fatalf("reviewer sync = %+v, want FAILED", result.ReviewerSync)
+	}
+
+	e.gitlab.Err = nil
+	sync, err := e.integrations.PushReviewerSync(ctx, result.ReviewerSync.SyncID)
+	if err != nil {
+		t.Fatalf("PushReviewerSync: %v", err)
+	}
+	if sync.Status != models.SyncPushed || sync.PushedAt == nil {
+		t.Errorf("pushed again = %+v, want PUSHED", sync)
+	}
+}
@@ -0,0 +1,30 @@
+package service
+
+// Outcomes of reviewer selection reported to Metrics.
+const (
+	OutcomeFull        = "full"
+	OutcomePartial     = "partial"
+	OutcomeZero        = "zero"
+	OutcomeNoCandidate = "no_candidate"
+)
+
+// Results of deactivation reassignment attempts reported to Metrics.
+const (
+	ReassignmentSuccess = "success"
+	ReassignmentFailure = "failure"
+	ReassignmentSkipped = "skipped"
+)
+
+// Metrics counts the outcomes of reviewer selection and of handing over the
+// reviews of deactivated users.
+type Metrics interface {
+	AssignmentOutcome(operation, outcome string)
+	DeactivationReassignment(result string)
+}
+
+// discardMetrics drops the outcomes of dry runs.
+type discardMetrics struct{}
+
+func (discardMetrics) AssignmentOutcome(operation, outcome string) {}
+
+func (discardMetrics) DeactivationReassignment(result string) {}
@@ -1,179 +1,637 @@
 package service
 
 import (
+	"context"
 	"errors"
-	"math/rand"
+	"log/slog"
 	"time"
 
+	"pr-reviewer-service/internal/logging"
 	"pr-reviewer-service/internal/models"
 	"pr-reviewer-service/internal/repository"
 )
 
 var (
-	ErrAuthorNotFound    = errors.New("author not found")
-	ErrTeamNotFound      = errors.New("team not found")
-	ErrPRMerged          = errors.New("PR is already merged")
+	ErrAuthorNotFound      = errors.New("author not found")
+	ErrTeamNotFound        = errors.New("team not found")
+	ErrPRMerged            = errors.New("PR is already merged")
 	ErrReviewerNotAssigned = errors.New("reviewer is not assigned")
-	ErrNoCandidate       = errors.New("no active replacement candidate")
+	ErrNoCandidate         = errors.New("no active replacement candidate")
+	ErrNotEnoughReviewers  = errors.New("not enough active reviewers in team")
+	ErrAllAtCapacity       = errors.New("all candidates are at review capacity")
+	ErrPRClosed            = errors.New("PR is closed")
+	ErrPRDraft             = errors.New("PR is a draft")
+	ErrInvalidTransition   = errors.New("invalid PR status transition")
+	ErrNotApproved         = errors.New("PR does not have enough approvals")
+	ErrChangesRequested    = errors.New("PR has outstanding change requests")
+	ErrInvalidVerdict      = errors.New("invalid review verdict")
 )
 
+// ActorSystem is recorded in the audit log for changes made by the service itself.
+const ActorSystem = "system"
+
 type PullRequestService struct {
-	prRepo     *repository.PullRequestRepository
-	userRepo   *repository.UserRepository
-	teamRepo   *repository.TeamRepository
-	randSource *rand.Rand
+	prRepo           repository.PullRequestRepository
+	userRepo         repository.UserRepository
+	teamRepo         repository.TeamRepository
+	eventRepo        repository.EventRepository
+	availabilityRepo repository.AvailabilityRepository
+	selectors        *SelectorRegistry
+	notifier         Notifier
+	metrics          Metrics
+
+	transactor repository.Transactor
 }
 
 func NewPullRequestService(
-	prRepo *repository.PullRequestRepository,
-	userRepo *repository.UserRepository,
-	teamRepo *repository.TeamRepository,
+	prRepo repository.PullRequestRepository,
+	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+	eventRepo repository.EventRepository,
+	availabilityRepo repository.AvailabilityRepository,
+	selectors *SelectorRegistry,
+	notifier Notifier,
+	metrics Metrics,
+	transactor repository.Transactor,
 ) *PullRequestService {
 	return &PullRequestService{
-		prRepo:     prRepo,
-		userRepo:   userRepo,
-		teamRepo:   teamRepo,
-		randSource: rand.New(rand.NewSource(time.Now().UnixNano())),
+		prRepo:           prRepo,
+		userRepo:         userRepo,
+		teamRepo:         teamRepo,
+		eventRepo:        eventRepo,
+		availabilityRepo: availabilityRepo,
+		selectors:        selectors,
+		notifier:         notifier,
+		metrics:          metrics,
+		transactor:       transactor,
+	}
+}
+
+// DryRun runs fn on a copy of the service bound to a transaction that is
+// rolled back afterwards. The copy sends no webhooks and its strategies start
+// from the current round-robin cursors, so fn sees the same results the calls
+// would give for real.
+func (s *PullRequestService) DryRun(ctx context.Context, fn func(dry *PullRequestService) error) error {
+	return s.transactor.DryRun(ctx, func(repos *repository.Repositories) error {
+		return fn(s.bind(repos, discardNotifier{}, true))
+	})
+}
+
+// bind returns a copy of the service working on repos of a transaction and
+// sending notifications to notifier. A dry run starts from a copy of the
+// round-robin cursors instead of advancing them and is not counted in metrics.
+func (s *PullRequestService) bind(repos *repository.Repositories, notifier Notifier, dryRun bool) *PullRequestService {
+	metrics := s.metrics
+	if dryRun {
+		metrics = discardMetrics{}
+	}
+	return &PullRequestService{
+		prRepo:           repos.PullRequests,
+		userRepo:         repos.Users,
+		teamRepo:         repos.Teams,
+		eventRepo:        repos.Events,
+		availabilityRepo: s.availabilityRepo,
+		selectors:        s.selectors.fork(repos.Users, dryRun),
+		notifier:         notifier,
+		metrics:          metrics,
+
+		transactor: s.transactor,
 	}
 }
 
-func (s *PullRequestService) CreatePR(prID, prName, authorID string) (*models.PullRequest, error) {
-	author, err := s.userRepo.GetByID(authorID)
+// notifying returns a copy of the service that sends notifications to notifier.
+func (s *PullRequestService) notifying(notifier Notifier) *PullRequestService {
+	copied := *s
+	copied.notifier = notifier
+	return &copied
+}
+
+func (s *PullRequestService) CreatePR(ctx context.Context, prID, prName, authorID string, draft bool, changedFiles []string, actor string) (*models.PullRequest, error) {
+	return s.createPR(ctx, prID, prName, authorID, draft, changedFiles, actor, nil)
+}
+
+// CreatePRExplained creates the PR like CreatePR and also explains how its
+// reviewers were selected.
+func (s *PullRequestService) CreatePRExplained(
+	ctx context.Context,
+	prID, prName, authorID string,
+	draft bool,
+	changedFiles []string,
+	actor string,
+) (*models.PullRequest, *models.Explanation, error) {
+	e := newExplainer(s.userRepo, s.availabilityRepo, models.OperationCreate, false)
+	pr, err := s.createPR(ctx, prID, prName, authorID, draft, changedFiles, actor, e)
 	if err != nil {
+		return nil, nil, err
+	}
+	e.explanation.PullRequestID = prID
+	return pr, e.result(), nil
+}
+
+// ExplainCreate runs the reviewer selection of CreatePR for the author without
+// creating a PR. Stateful strategies are not advanced. When no reviewers can
+// be selected the reason is reported in the explanation.
+func (s *PullRequestService) ExplainCreate(ctx context.Context, authorID string, changedFiles []string) (*models.Explanation, error) {
+	ctx = logging.With(ctx, "author_id", authorID)
+	author, err := s.userRepo.GetByID(ctx, authorID)
+	if err == repository.ErrUserNotFound {
 		return nil, ErrAuthorNotFound
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	e := newExplainer(s.userRepo, s.availabilityRepo, models.OperationCreate, true)
+	_, err = s.pickReviewers(ctx, author, changedFiles, e)
+	if isSelectionError(err) {
+		e.explanation.Error = err.Error()
+	} else if err != nil {
+		return nil, err
+	}
+	return e.result(), nil
+}
 
-	candidates, err := s.userRepo.GetActiveUsersByTeam(author.TeamName, authorID)
+func (s *PullRequestService) createPR(
+	ctx context.Context,
+	prID, prName, authorID string,
+	draft bool,
+	changedFiles []string,
+	actor string,
+	e *explainer,
+) (*models.PullRequest, error) {
+	ctx = logging.With(ctx, "pull_request_id", prID, "author_id", authorID)
+	author, err := s.userRepo.GetByID(ctx, authorID)
+	if err == repository.ErrUserNotFound {
+		return nil, ErrAuthorNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	var reviewers []string
-	maxReviewers := 2
-	if len(candidates) < maxReviewers {
-		maxReviewers = len(candidates)
+	pr := &models.PullRequest{
+		PullRequestID:   prID,
+		PullRequestName: prName,
+		AuthorID:        authorID,
+		Status:          models.StatusOpen,
+		ChangedFiles:    changedFiles,
 	}
 
-	if maxReviewers > 0 {
-		shuffled := make([]*models.User, len(candidates))
-		copy(shuffled, candidates)
-		s.shuffleUsers(shuffled)
-		
-		for i := 0; i < maxReviewers; i++ {
-			reviewers = append(reviewers, shuffled[i].UserID)
+	var assigned *assignment
+	if draft {
+		_, err = s.teamRepo.GetSettings(ctx, author.TeamName)
+		if err != nil {
+			if err == repository.ErrTeamNotFound {
+				return nil, ErrTeamNotFound
+			}
+			return nil, err
+		}
+		pr.Status = models.StatusDraft
+	} else {
+		assigned, err = s.pickReviewers(ctx, author, changedFiles, e)
+		s.countAssignment(models.OperationCreate, assigned, err)
+		if err != nil {
+			return nil, err
 		}
+		pr.AssignedReviewers = assigned.reviewers
+		pr.FallbackReviewers = assigned.fallback
 	}
 
-	pr := &models.PullRequest{
-		PullRequestID:     prID,
-		PullRequestName:   prName,
-		AuthorID:          authorID,
-		Status:            models.StatusOpen,
-		AssignedReviewers: reviewers,
+	err = s.prRepo.Create(ctx, pr)
+	if err != nil {
+		return nil, err
 	}
 
-	err = s.prRepo.Create(pr)
+	s.record(ctx, &models.Event{
+		EventType:     models.EventPRCreated,
+		PullRequestID: prID,
+		UserID:        authorID,
+		Actor:         actor,
+		Details:       string(pr.Status),
+	})
+	s.recordAssigned(ctx, prID, assigned, actor)
+
+	created, err := s.prRepo.GetByID(ctx, prID)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.prRepo.GetByID(prID)
+	err = s.notifier.Notify(ctx, models.WebhookPRCreated, map[string]interface{}{"pr": created})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *PullRequestService) MergePR(ctx context.Context, prID, actor string) (*models.PullRequest, error) {
+	return s.merge(ctx, prID, actor, true)
+}
+
+// RecordExternalMerge marks the PR merged after it was merged on the code
+// host. The merge has already happened there, so the approval gate of the
+// author's team does not apply.
+func (s *PullRequestService) RecordExternalMerge(ctx context.Context, prID, actor string) (*models.PullRequest, error) {
+	return s.merge(ctx, prID, actor, false)
 }
 
-func (s *PullRequestService) MergePR(prID string) (*models.PullRequest, error) {
-	err := s.prRepo.Merge(prID)
+func (s *PullRequestService) merge(ctx context.Context, prID, actor string, checkApprovals bool) (*models.PullRequest, error) {
+	ctx = logging.With(ctx, "pull_request_id", prID)
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pr.Status == models.StatusMerged {
+		return pr, nil
+	}
+	if err := checkTransition(pr.Status, models.StatusMerged); err != nil {
+		return nil, err
+	}
+
+	if checkApprovals {
+		if err := s.checkApprovals(ctx, pr); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.prRepo.Merge(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, &models.Event{EventType: models.EventMerged, PullRequestID: prID, Actor: actor})
+
+	merged, err := s.prRepo.GetByID(ctx, prID)
 	if err != nil {
 		return nil, err
 	}
-	return s.prRepo.GetByID(prID)
+
+	err = s.notifier.Notify(ctx, models.WebhookPRMerged, map[string]interface{}{"pr": merged})
+	if err != nil {
+		return nil, err
+	}
+
+	return merged, nil
 }
 
-func (s *PullRequestService) ReassignReviewer(prID, oldUserID string) (*models.PullRequest, string, error) {
-	pr, err := s.prRepo.GetByID(prID)
+// checkApprovals enforces the required_approvals setting of the author's team.
+func (s *PullRequestService) checkApprovals(ctx context.Context, pr *models.PullRequest) error {
+	author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+	if err == repository.ErrUserNotFound {
+		return ErrAuthorNotFound
+	}
 	if err != nil {
-		return nil, "", err
+		return err
+	}
+	settings, err := s.teamRepo.GetSettings(ctx, author.TeamName)
+	if err != nil {
+		return err
+	}
+	if pr.ApprovalCount() < settings.RequiredApprovals {
+		return ErrNotApproved
+	}
+	if settings.RequiredApprovals > 0 && pr.HasChangesRequested() {
+		return ErrChangesRequested
 	}
+	return nil
+}
 
-	if pr.Status == models.StatusMerged {
-		return nil, "", ErrPRMerged
+// SubmitReview stores the verdict of an assigned reviewer. A repeated call
+// replaces the previous verdict of the same reviewer.
+func (s *PullRequestService) SubmitReview(ctx context.Context, prID, reviewerID string, verdict models.ReviewVerdict) (*models.PullRequest, error) {
+	ctx = logging.With(ctx, "pull_request_id", prID, "reviewer_id", reviewerID)
+	if !verdict.IsValid() {
+		return nil, ErrInvalidVerdict
+	}
+
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pr.Status != models.StatusOpen {
+		return nil, statusError(pr.Status)
 	}
 
 	assigned := false
-	for _, reviewerID := range pr.AssignedReviewers {
-		if reviewerID == oldUserID {
+	for _, assignedReviewerID := range pr.AssignedReviewers {
+		if assignedReviewerID == reviewerID {
 			assigned = true
 			break
 		}
 	}
 	if !assigned {
-		return nil, "", ErrReviewerNotAssigned
+		return nil, ErrReviewerNotAssigned
 	}
 
-	oldReviewer, err := s.userRepo.GetByID(oldUserID)
+	err = s.prRepo.SubmitReview(ctx, prID, &models.Review{
+		UserID:      reviewerID,
+		Verdict:     verdict,
+		SubmittedAt: time.Now(),
+	})
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
-	candidates, err := s.userRepo.GetActiveUsersByTeam(oldReviewer.TeamName, oldUserID)
+	s.record(ctx, &models.Event{
+		EventType:     models.EventReviewSubmitted,
+		PullRequestID: prID,
+		UserID:        reviewerID,
+		Actor:         reviewerID,
+		Details:       string(verdict),
+	})
+
+	return s.prRepo.GetByID(ctx, prID)
+}
+
+func (s *PullRequestService) ClosePR(ctx context.Context, prID, actor string) (*models.PullRequest, error) {
+	ctx = logging.With(ctx, "pull_request_id", prID)
+	pr, err := s.prRepo.GetByID(ctx, prID)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
-	filtered := make([]*models.User, 0)
-	for _, candidate := range candidates {
-		if candidate.UserID != pr.AuthorID {
-			filtered = append(filtered, candidate)
-		}
+	if pr.Status == models.StatusClosed {
+		return pr, nil
+	}
+	if err := checkTransition(pr.Status, models.StatusClosed); err != nil {
+		return nil, err
 	}
-	candidates = filtered
 
-	filtered = make([]*models.User, 0)
-	for _, candidate := range candidates {
-		isAlreadyAssigned := false
-		for _, assignedReviewerID := range pr.AssignedReviewers {
-			if candidate.UserID == assignedReviewerID && candidate.UserID != oldUserID {
-				isAlreadyAssigned = true
-				break
-			}
+	err = s.prRepo.Close(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, &models.Event{EventType: models.EventClosed, PullRequestID: prID, Actor: actor})
+
+	return s.prRepo.GetByID(ctx, prID)
+}
+
+// ReopenPR returns a closed PR to OPEN. Previously assigned reviewers are kept;
+// a PR that was closed without reviewers gets them assigned as on creation.
+func (s *PullRequestService) ReopenPR(ctx context.Context, prID, actor string) (*models.PullRequest, error) {
+	ctx = logging.With(ctx, "pull_request_id", prID)
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pr.Status == models.StatusOpen {
+		return pr, nil
+	}
+	if pr.Status != models.StatusClosed {
+		return nil, statusError(pr.Status)
+	}
+
+	return s.openPR(ctx, pr, models.EventReopened, actor)
+}
+
+// MarkReady moves a draft PR to OPEN and assigns reviewers.
+func (s *PullRequestService) MarkReady(ctx context.Context, prID, actor string) (*models.PullRequest, error) {
+	ctx = logging.With(ctx, "pull_request_id", prID)
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pr.Status == models.StatusOpen {
+		return pr, nil
+	}
+	if pr.Status != models.StatusDraft {
+		return nil, statusError(pr.Status)
+	}
+
+	return s.openPR(ctx, pr, models.EventMarkedReady, actor)
+}
+
+func (s *PullRequestService) openPR(ctx context.Context, pr *models.PullRequest, eventType models.EventType, actor string) (*models.PullRequest, error) {
+	assigned := &assignment{}
+	if len(pr.AssignedReviewers) == 0 {
+		author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+		if err == repository.ErrUserNotFound {
+			return nil, ErrAuthorNotFound
 		}
-		if !isAlreadyAssigned {
-			filtered = append(filtered, candidate)
+		if err != nil {
+			return nil, err
 		}
+
+		assigned, err = s.pickReviewers(ctx, author, pr.ChangedFiles, nil)
+		s.countAssignment(models.OperationCreate, assigned, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := s.prRepo.MarkOpen(ctx, pr.PullRequestID, assigned.reviewers, assigned.fallback)
+	if err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, &models.Event{EventType: eventType, PullRequestID: pr.PullRequestID, Actor: actor})
+	s.recordAssigned(ctx, pr.PullRequestID, assigned, actor)
+
+	return s.prRepo.GetByID(ctx, pr.PullRequestID)
+}
+
+func (s *PullRequestService) ReassignReviewer(ctx context.Context, prID, oldUserID, actor string) (*models.PullRequest, string, error) {
+	return s.reassignReviewer(ctx, prID, oldUserID, actor, nil)
+}
+
+// ReassignReviewerExplained replaces the reviewer like ReassignReviewer and
+// also explains how the replacement was selected.
+func (s *PullRequestService) ReassignReviewerExplained(
+	ctx context.Context,
+	prID, oldUserID, actor string,
+) (*models.PullRequest, string, *models.Explanation, error) {
+	e := newExplainer(s.userRepo, s.availabilityRepo, models.OperationReassign, false)
+	pr, newUserID, err := s.reassignReviewer(ctx, prID, oldUserID, actor, e)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return pr, newUserID, e.result(), nil
+}
+
+// ExplainReassign runs the replacement selection of ReassignReviewer without
+// changing the PR. Stateful strategies are not advanced.
+func (s *PullRequestService) ExplainReassign(ctx context.Context, prID, oldUserID string) (*models.Explanation, error) {
+	ctx = logging.With(ctx, "pull_request_id", prID, "old_user_id", oldUserID)
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	e := newExplainer(s.userRepo, s.availabilityRepo, models.OperationReassign, true)
+	_, err = s.pickReplacement(ctx, pr, oldUserID, e)
+	if isSelectionError(err) {
+		e.explanation.Error = err.Error()
+	} else if err != nil {
+		return nil, err
+	}
+	return e.result(), nil
+}
+
+func (s *PullRequestService) reassignReviewer(ctx context.Context, prID, oldUserID, actor string, e *explainer) (*models.PullRequest, string, error) {
+	ctx = logging.With(ctx, "pull_request_id", prID, "old_user_id", oldUserID)
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, "", err
 	}
-	candidates = filtered
 
-	if len(candidates) == 0 {
-		return nil, "", ErrNoCandidate
+	replaced, err := s.pickReplacement(ctx, pr, oldUserID, e)
+	if isSelectionError(err) {
+		s.metrics.AssignmentOutcome(models.OperationReassign, OutcomeNoCandidate)
+	}
+	if err != nil {
+		return nil, "", err
 	}
+	s.metrics.AssignmentOutcome(models.OperationReassign, OutcomeFull)
+	newReviewer := replaced.reviewer
 
-	selected := candidates[s.randSource.Intn(len(candidates))]
+	err = s.prRepo.ReassignReviewer(ctx, prID, oldUserID, newReviewer.UserID, replaced.fallback)
+	if err != nil {
+		return nil, "", err
+	}
 
-	err = s.prRepo.ReassignReviewer(prID, oldUserID, selected.UserID)
+	event := &models.Event{
+		EventType:      models.EventReviewerReplaced,
+		PullRequestID:  prID,
+		UserID:         newReviewer.UserID,
+		PreviousUserID: oldUserID,
+		Actor:          actor,
+		Strategy:       replaced.strategy,
+	}
+	if replaced.fallback {
+		event.Details = models.EventDetailsFallback
+	}
+	s.record(ctx, event)
+
+	updatedPR, err := s.prRepo.GetByID(ctx, prID)
 	if err != nil {
 		return nil, "", err
 	}
 
-	updatedPR, err := s.prRepo.GetByID(prID)
+	err = s.notifier.Notify(ctx, models.WebhookPRReassigned, &models.PRReassigned{
+		PR:         updatedPR,
+		OldUserID:  oldUserID,
+		ReplacedBy: newReviewer.UserID,
+	})
 	if err != nil {
 		return nil, "", err
 	}
 
-	return updatedPR, selected.UserID, nil
+	return updatedPR, newReviewer.UserID, nil
 }
 
-func (s *PullRequestService) GetPRsByReviewer(userID string) ([]*models.PullRequestShort, error) {
-	_, err := s.userRepo.GetByID(userID)
+func (s *PullRequestService) GetPRsByReviewer(ctx context.Context, userID string) ([]*models.PullRequestShort, error) {
+	ctx = logging.With(ctx, "user_id", userID)
+	_, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.prRepo.GetPRsByReviewer(ctx, userID)
+}
+
+// filterByCapacity drops candidates who already review max_open_reviews OPEN PRs.
+func (s *PullRequestService) filterByCapacity(ctx context.Context, candidates []*models.User) ([]*models.User, error) {
+	var limited []string
+	for _, candidate := range candidates {
+		if candidate.MaxOpenReviews != nil {
+			limited = append(limited, candidate.UserID)
+		}
+	}
+	if len(limited) == 0 {
+		return candidates, nil
+	}
+
+	loads, err := s.userRepo.GetOpenReviewCounts(ctx, limited)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.prRepo.GetPRsByReviewer(userID)
+	available := make([]*models.User, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.HasCapacity(loads[candidate.UserID]) {
+			available = append(available, candidate)
+		}
+	}
+	return available, nil
+}
+
+func checkTransition(from, to models.PullRequestStatus) error {
+	if from.CanTransitionTo(to) {
+		return nil
+	}
+	return statusError(from)
+}
+
+// statusError reports why an operation is not allowed for a PR in the given status.
+func statusError(status models.PullRequestStatus) error {
+	switch status {
+	case models.StatusMerged:
+		return ErrPRMerged
+	case models.StatusClosed:
+		return ErrPRClosed
+	case models.StatusDraft:
+		return ErrPRDraft
+	}
+	return ErrInvalidTransition
 }
 
-func (s *PullRequestService) shuffleUsers(users []*models.User) {
-	for i := len(users) - 1; i > 0; i-- {
-		j := s.randSource.Intn(i + 1)
-		users[i], users[j] = users[j], users[i]
+// countAssignment reports the outcome of selecting reviewers for a PR: whether
+// all, some or none of the slots were filled, or selection failed.
+func (s *PullRequestService) countAssignment(operation string, assigned *assignment, err error) {
+	switch {
+	case isSelectionError(err):
+		s.metrics.AssignmentOutcome(operation, OutcomeNoCandidate)
+	case err != nil:
+	case len(assigned.reviewers) >= assigned.wanted:
+		s.metrics.AssignmentOutcome(operation, OutcomeFull)
+	case len(assigned.reviewers) > 0:
+		s.metrics.AssignmentOutcome(operation, OutcomePartial)
+	default:
+		s.metrics.AssignmentOutcome(operation, OutcomeZero)
 	}
 }
 
+// record appends events to the audit log. The change itself is already stored
+// at this point, so a failed write is logged instead of failing the operation.
+func (s *PullRequestService) record(ctx context.Context, events ...*models.Event) {
+	for _, event := range events {
+		if err := s.eventRepo.Append(ctx, event); err != nil {
+			slog.ErrorContext(ctx, "failed to record event",
+				"event_type", event.EventType, "pull_request_id", event.PullRequestID, "error", err)
+		}
+	}
+}
+
+// recordAssigned records an event per assigned reviewer. Reviewers taken from
+// a fallback team are marked with "fallback" in details.
+func (s *PullRequestService) recordAssigned(ctx context.Context, prID string, assigned *assignment, actor string) {
+	if assigned == nil {
+		return
+	}
+	for _, reviewerID := range assigned.reviewers {
+		event := &models.Event{
+			EventType:     models.EventReviewerAssigned,
+			PullRequestID: prID,
+			UserID:        reviewerID,
+			Actor:         actor,
+			Strategy:      assigned.strategy,
+		}
+		if containsUserID(assigned.fallback, reviewerID) {
+			event.Details = models.EventDetailsFallback
+		}
+		s.record(ctx, event)
+	}
+}
+
+func containsUserID(userIDs []string, userID string) bool {
+	for _, id := range userIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,467 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+// withBackend creates the backend team of alice, bob, carol and dave.
+func withBackend(t *testing.T, e *testEnv) {
+	t.Helper()
+	e.addTeam(t, "backend", "alice", "bob", "carol", "dave")
+}
+
+// withFallback creates the backend team of alice and bob, which falls back to
+// the ops team of erin.
+func withFallback(t *testing.T, e *testEnv) {
+	t.Helper()
+	e.addTeam(t, "backend", "alice", "bob")
+	e.addTeam(t, "ops", "erin")
+	e.updateSettings(t, "backend", func(settings *models.TeamSettings) {
+		settings.FallbackTeams = []string{"ops"}
+	})
+}
+
+func TestCreatePR(t *testing.T) {
+	tests := []struct {
+		name         string
+		setup        func(t *testing.T, e *testEnv)
+		authorID     string
+		draft        bool
+		wantErr      error
+		wantStatus   models.PullRequestStatus
+		wantAssigned []string
+		wantFallback []string
+		wantOutcome  string
+	}{
+		{
+			name:         "reviewers from the team",
+			setup:        withBackend,
+			authorID:     "alice",
+			wantStatus:   models.StatusOpen,
+			wantAssigned: []string{"bob", "carol"},
+			wantOutcome:  OutcomeFull,
+		},
+		{
+			name:       "draft waits for ready",
+			setup:      withBackend,
+			authorID:   "alice",
+			draft:      true,
+			wantStatus: models.StatusDraft,
+		},
+		{
+			name: "reviewer at capacity is skipped",
+			setup: func(t *testing.T, e *testEnv) {
+				withBackend(t, e)
+				e.setCapacity(t, "bob", 0)
+			},
+			authorID:     "alice",
+			wantStatus:   models.StatusOpen,
+			wantAssigned: []string{"carol", "dave"},
+			wantOutcome:  OutcomeFull,
+		},
+		{
+			name: "assign fewer when everyone is at capacity",
+			setup: func(t *testing.T, e *testEnv) {
+				withBackend(t, e)
+				for _, userID := range []string{"bob", "carol", "dave"} {
+					e.setCapacity(t, userID, 0)
+				}
+			},
+			authorID:    "alice",
+			wantStatus:  models.StatusOpen,
+			wantOutcome: OutcomeZero,
+		},
+		{
+			name: "fail when everyone is at capacity",
+			setup: func(t *testing.T, e *testEnv) {
+				withBackend(t, e)
+				e.updateSettings(t, "backend", func(settings *models.TeamSettings) {
+					settings.CapacityPolicy = models.CapacityPolicyFail
+				})
+				for _, userID := range []string{"bob", "carol", "dave"} {
+					e.setCapacity(t, userID, 0)
+				}
+			},
+			authorID:    "alice",
+			wantErr:     ErrAllAtCapacity,
+			wantOutcome: OutcomeNoCandidate,
+		},
+		{
+			name: "partial team",
+			setup: func(t *testing.T, e *testEnv) {
+				e.addTeam(t, "backend", "alice", "bob")
+			},
+			authorID:     "alice",
+			wantStatus:   models.StatusOpen,
+			wantAssigned: []string{"bob"},
+			wantOutcome:  OutcomePartial,
+		},
+		{
+			name: "fewer than min_reviewers",
+			setup: func(t *testing.T, e *testEnv) {
+				e.addTeam(t, "backend", "alice", "bob")
+				e.updateSettings(t, "backend", func(settings *models.TeamSettings) {
+					settings.MinReviewers = 2
+				})
+			},
+			authorID:    "alice",
+			wantErr:     ErrNotEnoughReviewers,
+			wantOutcome: OutcomeNoCandidate,
+		},
+		{
+			name:         "fallback team fills the missing slot",
+			setup:        withFallback,
+			authorID:     "alice",
+			wantStatus:   models.StatusOpen,
+			wantAssigned: []string{"bob", "erin"},
+			wantFallback: []string{"erin"},
+			wantOutcome:  OutcomeFull,
+		},
+		{
+			name:     "unknown author",
+			setup:    withBackend,
+			authorID: "zed",
+			wantErr:  ErrAuthorNotFound,
+		},
+		{
+			name: "existing PR",
+			setup: func(t *testing.T, e *testEnv) {
+				withBackend(t, e)
+				e.createPR(t, "pr-1", "bob")
+			},
+			authorID: "alice",
+			wantErr:  repository.ErrPRExists,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			e := newTestEnv(t)
+			tt.setup(t, e)
+			created := e.notifier.count(models.WebhookPRCreated)
+
+			pr, err := e.prs.CreatePR(ctx, "pr-1", "Add cache", tt.authorID, tt.draft, nil, "test")
+			if err != tt.wantErr {
+				t.Fatalf("CreatePR error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantOutcome != "" && e.metrics.count(models.OperationCreate+"/"+tt.wantOutcome) != 1 {
+				t.Errorf("outcome %s was not counted", tt.wantOutcome)
+			}
+			if err != nil {
+				return
+			}
+
+			if pr.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", pr.Status, tt.wantStatus)
+			}
+			if !sameUserIDs(pr.AssignedReviewers, tt.wantAssigned) {
+				t.Errorf("reviewers = %v, want %v", pr.AssignedReviewers, tt.wantAssigned)
+			}
+			if !sameUserIDs(pr.FallbackReviewers, tt.wantFallback) {
+				t.Errorf("fallback reviewers = %v, want %v", pr.FallbackReviewers, tt.wantFallback)
+			}
+			if got := e.notifier.count(models.WebhookPRCreated) - created; got != 1 {
+				t.Errorf("%s notifications = %d, want 1", models.WebhookPRCreated, got)
+			}
+		})
+	}
+}
+
+func TestReassignReviewer(t *testing.T) {
+	tests := []struct {
+		name         string
+		setup        func(t *testing.T, e *testEnv)
+		afterCreate  func(t *testing.T, e *testEnv)
+		oldUserID    string
+		wantErr      error
+		wantNew      string
+		wantFallback []string
+	}{
+		{
+			name:      "replaced from the team",
+			setup:     withBackend,
+			oldUserID: "bob",
+			wantNew:   "dave",
+		},
+		{
+			name: "nobody left",
+			setup: func(t *testing.T, e *testEnv) {
+				e.addTeam(t, "backend", "alice", "bob", "carol")
+			},
+			oldUserID: "bob",
+			wantErr:   ErrNoCandidate,
+		},
+		{
+			name: "everyone left is at capacity",
+			setup: func(t *testing.T, e *testEnv) {
+				withBackend(t, e)
+				e.setCapacity(t, "dave", 0)
+			},
+			oldUserID: "bob",
+			wantErr:   ErrNoCandidate,
+		},
+		{
+			name: "everyone left is at capacity, fail policy",
+			setup: func(t *testing.T, e *testEnv) {
+				withBackend(t, e)
+				e.setCapacity(t, "dave", 0)
+				e.updateSettings(t, "backend", func(settings *models.TeamSettings) {
+					settings.CapacityPolicy = models.CapacityPolicyFail
+				})
+			},
+			oldUserID: "bob",
+			wantErr:   ErrAllAtCapacity,
+		},
+		{
+			name: "replaced from the fallback team",
+			setup: func(t *testing.T, e *testEnv) {
+				e.addTeam(t, "backend", "alice", "bob", "carol")
+				e.addTeam(t, "ops", "erin")
+				e.updateSettings(t, "backend", func(settings *models.TeamSettings) {
+					settings.FallbackTeams = []string{"ops"}
+				})
+			},
+			oldUserID:    "bob",
+			wantNew:      "erin",
+			wantFallback: []string{"erin"},
+		},
+		{
+			name:      "reviewer not assigned",
+			setup:     withBackend,
+			oldUserID: "dave",
+			wantErr:   ErrReviewerNotAssigned,
+		},
+		{
+			name:  "merged PR",
+			setup: withBackend,
+			afterCreate: func(t *testing.T, e *testEnv) {
+				if _, err := e.prs.MergePR(context.Background(), "pr-1", "test"); err != nil {
+					t.Fatalf("MergePR: %v", err)
+				}
+			},
+			oldUserID: "bob",
+			wantErr:   ErrPRMerged,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			e := newTestEnv(t)
+			tt.setup(t, e)
+			if before := e.createPR(t, "pr-1", "alice"); !sameUserIDs(before.AssignedReviewers, []string{"bob", "carol"}) {
+				t.Fatalf("reviewers = %v, want [bob carol]", before.AssignedReviewers)
+			}
+			if tt.afterCreate != nil {
+				tt.afterCreate(t, e)
+			}
+
+			pr, newUserID, err := e.prs.ReassignReviewer(ctx, "pr-1", tt.oldUserID, "test")
+			if err != tt.wantErr {
+				t.Fatalf("ReassignReviewer error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if got := e.notifier.count(models.WebhookPRReassigned); got != 0 {
+					t.Errorf("%s notifications = %d, want 0", models.WebhookPRReassigned, got)
+				}
+				return
+			}
+
+			if newUserID != tt.wantNew || containsUserID(pr.AssignedReviewers, tt.oldUserID) || !containsUserID(pr.AssignedReviewers, tt.wantNew) {
+				t.Errorf("reviewers = %v after replacing %s by %s, want %s instead", pr.AssignedReviewers, tt.oldUserID, newUserID, tt.wantNew)
+			}
+			if !sameUserIDs(pr.FallbackReviewers, tt.wantFallback) {
+				t.Errorf("fallback reviewers = %v, want %v", pr.FallbackReviewers, tt.wantFallback)
+			}
+
+			events, err := e.repos.Events.GetByPullRequest(ctx, "pr-1")
+			if err != nil {
+				t.Fatalf("GetByPullRequest: %v", err)
+			}
+			last := events[len(events)-1]
+			if last.EventType != models.EventReviewerReplaced || last.UserID != tt.wantNew || last.PreviousUserID != tt.oldUserID {
+				t.Errorf("last event = %+v, want %s replaced by %s", last, tt.oldUserID, tt.wantNew)
+			}
+			if got := e.notifier.count(models.WebhookPRReassigned); got != 1 {
+				t.Errorf("%s notifications = %d, want 1", models.WebhookPRReassigned, got)
+			}
+		})
+	}
+}
+
+func TestMergePR(t *testing.T) {
+	tests := []struct {
+		name              string
+		requiredApprovals int
+		draft             bool
+		verdicts          map[string]models.ReviewVerdict
+		wantErr           error
+	}{
+		{name: "no approvals required"},
+		{name: "not approved", requiredApprovals: 1, wantErr: ErrNotApproved},
+		{
+			name:              "approved",
+			requiredApprovals: 1,
+			verdicts:          map[string]models.ReviewVerdict{"bob": models.VerdictApproved},
+		},
+		{
+			name:              "comments do not approve",
+			requiredApprovals: 1,
+			verdicts:          map[string]models.ReviewVerdict{"bob": models.VerdictCommented},
+			wantErr:           ErrNotApproved,
+		},
+		{
+			name:              "changes requested",
+			requiredApprovals: 1,
+			verdicts: map[string]models.ReviewVerdict{
+				"bob":   models.VerdictApproved,
+				"carol": models.VerdictChangesRequested,
+			},
+			wantErr: ErrChangesRequested,
+		},
+		{
+			name:     "change requests do not block without required approvals",
+			verdicts: map[string]models.ReviewVerdict{"carol": models.VerdictChangesRequested},
+		},
+		{name: "draft", draft: true, wantErr: ErrPRDraft},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			e := newTestEnv(t)
+			withBackend(t, e)
+			e.updateSettings(t, "backend", func(settings *models.TeamSettings) {
+				settings.RequiredApprovals = tt.requiredApprovals
+			})
+			if _, err := e.prs.CreatePR(ctx, "pr-1", "pr-1", "alice", tt.draft, nil, "test"); err != nil {
+				t.Fatalf("CreatePR: %v", err)
+			}
+			for reviewerID, verdict := range tt.verdicts {
+				if _, err := e.prs.SubmitReview(ctx, "pr-1", reviewerID, verdict); err != nil {
+					t.Fatalf("SubmitReview(%s): %v", reviewerID, err)
+				}
+			}
+
+			merged, err := e.prs.MergePR(ctx, "pr-1", "test")
+			if err != tt.wantErr {
+				t.Fatalf("MergePR error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if pr := e.getPR(t, "pr-1"); pr.Status == models.StatusMerged {
+					t.Error("PR was merged")
+				}
+				return
+			}
+			if merged.Status != models.StatusMerged || merged.MergedAt == nil {
+				t.Fatalf("PR = %+v, want merged", merged)
+			}
+
+			// Merging again changes nothing and notifies nobody.
+			again, err := e.prs.MergePR(ctx, "pr-1", "test")
+			if err != nil {
+				t.Fatalf("second MergePR: %v", err)
+			}
+			if !again.MergedAt.Equal(*merged.MergedAt) {
+				t.Errorf("merged_at moved from %v to %v", merged.MergedAt, again.MergedAt)
+			}
+			if got := e.notifier.count(models.WebhookPRMerged); got != 1 {
+				t.Errorf("%s notifications = %d, want 1", models.WebhookPRMerged, got)
+			}
+		})
+	}
+}
+
+// TestDryRun checks that dry runs report what the call would do without
+// changing the PRs, advancing round-robin, counting metrics or notifying.
+func TestDryRun(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(ctx context.Context, dry *PullRequestService) ([]string, error)
+		// real is the call the dry run previews.
+		real func(ctx context.Context, e *testEnv) ([]string, error)
+	}{
+		{
+			name: "create",
+			run: func(ctx context.Context, dry *PullRequestService) ([]string, error) {
+				pr, err := dry.CreatePR(ctx, "pr-2", "pr-2", "alice", false, nil, "test")
+				if err != nil {
+					return nil, err
+				}
+				return pr.AssignedReviewers, nil
+			},
+			real: func(ctx context.Context, e *testEnv) ([]string, error) {
+				pr, err := e.prs.CreatePR(ctx, "pr-2", "pr-2", "alice", false, nil, "test")
+				if err != nil {
+					return nil, err
+				}
+				return pr.AssignedReviewers, nil
+			},
+		},
+		{
+			name: "reassign",
+			run: func(ctx context.Context, dry *PullRequestService) ([]string, error) {
+				pr, _, err := dry.ReassignReviewer(ctx, "pr-1", "bob", "test")
+				if err != nil {
+					return nil, err
+				}
+				return pr.AssignedReviewers, nil
+			},
+			real: func(ctx context.Context, e *testEnv) ([]string, error) {
+				pr, _, err := e.prs.ReassignReviewer(ctx, "pr-1", "bob", "test")
+				if err != nil {
+					return nil, err
+				}
+				return pr.AssignedReviewers, nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			e := newTestEnv(t)
+			e.addTeam(t, "backend", "alice", "bob", "carol", "dave", "erin")
+			e.createPR(t, "pr-1", "alice")
+			events, _ := e.repos.Events.GetByPullRequest(ctx, "pr-1")
+			notifications := len(e.notifier.events)
+			counted := e.metrics.count(models.OperationCreate+"/"+OutcomeFull) + e.metrics.count(models.OperationReassign+"/"+OutcomeFull)
+
+			var preview []string
+			err := e.prs.DryRun(ctx, func(dry *PullRequestService) error {
+				var err error
+				preview, err = tt.run(ctx, dry)
+				return err
+			})
+			if err != nil {
+				t.Fatalf("dry run: %v", err)
+			}
+
+			if pr := e.getPR(t, "pr-1"); !sameUserIDs(pr.AssignedReviewers, []string{"bob", "carol"}) {
+				t.Errorf("pr-1 reviewers = %v after a dry run", pr.AssignedReviewers)
+			}
+			if _, err := e.repos.PullRequests.GetByID(ctx, "pr-2"); err != repository.ErrPRNotFound {
+				t.Errorf("pr-2 lookup error = %v after a dry run, want %v", err, repository.ErrPRNotFound)
+			}
+			if after, _ := e.repos.Events.GetByPullRequest(ctx, "pr-1"); len(after) != len(events) {
+				t.Errorf("events = %d after a dry run, want %d", len(after), len(events))
+			}
+			if len(e.notifier.events) != notifications {
+				t.Errorf("notifications = %v after a dry run", e.notifier.events[notifications:])
+			}
+			if got := e.metrics.count(models.OperationCreate+"/"+OutcomeFull) + e.metrics.count(models.OperationReassign+"/"+OutcomeFull); got != counted {
+				t.Errorf("counted outcomes = %d after a dry run, want %d", got, counted)
+			}
+
+			// The real call picks what the dry run showed.
+			got, err := tt.real(ctx, e)
+			if err != nil {
+				t.Fatalf("real call: %v", err)
+			}
+			if !sameUserIDs(got, preview) {
+				t.Errorf("reviewers = %v, dry run showed %v", got, preview)
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"pr-reviewer-service/internal/logging"
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+var ErrInvalidLimit = errors.New("limit must not be negative")
+
+type ReactivationService struct {
+	userRepo  repository.UserRepository
+	prRepo    repository.PullRequestRepository
+	eventRepo repository.EventRepository
+	prService *PullRequestService
+	notifier  Notifier
+}
+
+func NewReactivationService(
+	userRepo repository.UserRepository,
+	prRepo repository.PullRequestRepository,
+	eventRepo repository.EventRepository,
+	prService *PullRequestService,
+	notifier Notifier,
+) *ReactivationService {
+	return &ReactivationService{
+		userRepo:  userRepo,
+		prRepo:    prRepo,
+		eventRepo: eventRepo,
+		prService: prService,
+		notifier:  notifier,
+	}
+}
+
+// ReactivateUser activates the user and hands open reviews over to them: first
+// those they were replaced on, when req.RestoreReviews is set, then up to
+// req.Limit reviews of the most loaded active teammates. Reviews are only moved
+// while the user has capacity and is not in an unavailability window, and never
+// so that a PR loses its last code owner. The reactivation and all moves are
+// committed together; webhooks are sent after the commit.
+func (s *ReactivationService) ReactivateUser(ctx context.Context, req *models.ReactivationRequest, actor string) (*models.ReactivationResponse, error) {
+	ctx = logging.With(ctx, "user_id", req.UserID)
+	if req.Limit < 0 {
+		return nil, ErrInvalidLimit
+	}
+
+	notifier := &deferredNotifier{target: s.notifier}
+
+	var response *models.ReactivationResponse
+	err := s.prService.transactor.Atomic(ctx, func(repos *repository.Repositories) error {
+		var err error
+		response, err = s.bind(repos, notifier).reactivate(ctx, req, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := notifier.flush(); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *ReactivationService) bind(repos *repository.Repositories, notifier Notifier) *ReactivationService {
+	return &ReactivationService{
+		userRepo:  repos.Users,
+		prRepo:    repos.PullRequests,
+		eventRepo: repos.Events,
+		prService: s.prService.bind(repos, notifier, false),
+		notifier:  notifier,
+	}
+}
+
+func (s *ReactivationService) reactivate(ctx context.Context, req *models.ReactivationRequest, actor string) (*models.ReactivationResponse, error) {
+	err := s.userRepo.SetIsActive(ctx, req.UserID, true)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.eventRepo.Append(ctx, &models.Event{
+		EventType: models.EventUserReactivated,
+		UserID:    user.UserID,
+		Actor:     actor,
+		Details:   user.TeamName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record reactivation: %w", err)
+	}
+
+	members, err := s.userRepo.GetActiveUsersByTeam(ctx, user.TeamName, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team users: %w", err)
+	}
+	memberIDs := make([]string, 0, len(members))
+	available := false
+	for _, member := range members {
+		memberIDs = append(memberIDs, member.UserID)
+		if member.UserID == user.UserID {
+			available = true
+		}
+	}
+
+	loads, err := s.userRepo.GetOpenReviewCounts(ctx, memberIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open review counts: %w", err)
+	}
+	response := &models.ReactivationResponse{
+		User:         user,
+		MovedReviews: make([]*models.MovedReview, 0),
+		LoadsBefore:  withZeroLoads(loads, memberIDs),
+	}
+
+	if available {
+		r := &reactivation{service: s, user: user, actor: actor, loads: withZeroLoads(loads, memberIDs)}
+		if req.RestoreReviews {
+			if err := r.restore(ctx); err != nil {
+				return nil, err
+			}
+		}
+		if err := r.rebalance(ctx, members, req.Limit); err != nil {
+			return nil, err
+		}
+		response.MovedReviews = r.moved
+	}
+
+	loads, err = s.userRepo.GetOpenReviewCounts(ctx, memberIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open review counts: %w", err)
+	}
+	response.LoadsAfter = withZeroLoads(loads, memberIDs)
+	return response, nil
+}
+
+type reactivation struct {
+	service *ReactivationService
+	user    *models.User
+	actor   string
+	loads   map[string]int
+	moved   []*models.MovedReview
+}
+
+// restore hands back the reviews taken from the user since their deactivation.
+func (r *reactivation) restore(ctx context.Context) error {
+	events, err := r.service.eventRepo.GetByUser(ctx, r.user.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get events of user %s: %w", r.user.UserID, err)
+	}
+
+	var prIDs []string
+	holders := make(map[string]string)
+	for _, event := range events {
+		if event.EventType == models.EventUserDeactivated && event.UserID == r.user.UserID {
+			prIDs = nil
+			holders = make(map[string]string)
+			continue
+		}
+		if event.EventType != models.EventReviewerReplaced || event.PreviousUserID != r.user.UserID {
+			continue
+		}
+		if _, ok := holders[event.PullRequestID]; !ok {
+			prIDs = append(prIDs, event.PullRequestID)
+		}
+		holders[event.PullRequestID] = event.UserID
+	}
+
+	for _, prID := range prIDs {
+		if !r.user.HasCapacity(r.loads[r.user.UserID]) {
+			return nil
+		}
+
+		if _, err := r.moveReview(ctx, prID, holders[prID], models.ReactivationRestored); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rebalance only takes from teammates with at least two more open reviews, so
+// no one ends up with fewer reviews than the user.
+func (r *reactivation) rebalance(ctx context.Context, members []*models.User, limit int) error {
+	donors := make([]*models.User, 0, len(members))
+	for _, member := range members {
+		if member.UserID != r.user.UserID {
+			donors = append(donors, member)
+		}
+	}
+
+	openPRs := make(map[string][]*models.PullRequest)
+	for taken := 0; taken < limit && r.user.HasCapacity(r.loads[r.user.UserID]); {
+		sort.SliceStable(donors, func(i, j int) bool {
+			if r.loads[donors[i].UserID] != r.loads[donors[j].UserID] {
+				return r.loads[donors[i].UserID] > r.loads[donors[j].UserID]
+			}
+			return donors[i].UserID < donors[j].UserID
+		})
+		if len(donors) == 0 || r.loads[donors[0].UserID] < r.loads[r.user.UserID]+2 {
+			return nil
+		}
+		donor := donors[0]
+
+		prs, ok := openPRs[donor.UserID]
+		if !ok {
+			var err error
+			prs, err = r.service.prRepo.GetOpenPRsWithReviewer(ctx, donor.UserID)
+			if err != nil {
+				return fmt.Errorf("failed to get open PRs of user %s: %w", donor.UserID, err)
+			}
+		}
+
+		moved := false
+		for len(prs) > 0 && !moved {
+			pr := prs[0]
+			prs = prs[1:]
+
+			var err error
+			moved, err = r.moveReview(ctx, pr.PullRequestID, donor.UserID, models.ReactivationRebalanced)
+			if err != nil {
+				return err
+			}
+		}
+		openPRs[donor.UserID] = prs
+
+		if moved {
+			taken++
+		} else {
+			// Nothing left to take from this teammate.
+			donors = donors[1:]
+		}
+	}
+	return nil
+}
+
+// moveReview skips PRs that would lose their last code owner.
+func (r *reactivation) moveReview(ctx context.Context, prID, fromUserID string, reason models.ReactivationReason) (bool, error) {
+	ctx = logging.With(ctx, "pull_request_id", prID, "from_user_id", fromUserID)
+	pr, err := r.service.prRepo.GetByID(ctx, prID)
+	if err == repository.ErrPRNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if pr.Status != models.StatusOpen || !containsUserID(pr.AssignedReviewers, fromUserID) {
+		return false, nil
+	}
+	if pr.AuthorID == r.user.UserID || containsUserID(pr.AssignedReviewers, r.user.UserID) {
+		return false, nil
+	}
+
+	author, err := r.service.userRepo.GetByID(ctx, pr.AuthorID)
+	if err != nil {
+		return false, err
+	}
+	keepsOwner, err := r.service.prService.keepsOwner(ctx, pr, author.TeamName, fromUserID, r.user.UserID)
+	if err != nil {
+		return false, err
+	}
+	if !keepsOwner {
+		return false, nil
+	}
+
+	fallback := r.user.TeamName != author.TeamName
+	err = r.service.prRepo.ReassignReviewer(ctx, pr.PullRequestID, fromUserID, r.user.UserID, fallback)
+	if err == repository.ErrNotAssigned {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	err = r.service.eventRepo.Append(ctx, &models.Event{
+		EventType:      models.EventReviewerReplaced,
+		PullRequestID:  pr.PullRequestID,
+		UserID:         r.user.UserID,
+		PreviousUserID: fromUserID,
+		Actor:          r.actor,
+		Details:        models.EventDetailsReactivation,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to record reassignment: %w", err)
+	}
+
+	updatedPR, err := r.service.prRepo.GetByID(ctx, pr.PullRequestID)
+	if err != nil {
+		return false, err
+	}
+	err = r.service.notifier.Notify(ctx, models.WebhookPRReassigned, &models.PRReassigned{
+		PR:         updatedPR,
+		OldUserID:  fromUserID,
+		ReplacedBy: r.user.UserID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	r.loads[r.user.UserID]++
+	if _, ok := r.loads[fromUserID]; ok {
+		r.loads[fromUserID]--
+	}
+	r.moved = append(r.moved, &models.MovedReview{
+		PullRequestID: pr.PullRequestID,
+		FromUserID:    fromUserID,
+		Reason:        reason,
+	})
+	return true, nil
+}
+
+// withZeroLoads copies loads, adding users without open reviews.
+func withZeroLoads(loads map[string]int, userIDs []string) map[string]int {
+	result := make(map[string]int, len(userIDs))
+	for _, userID := range userIDs {
+		result[userID] = loads[userID]
+	}
+	return result
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+// newRebalanceEnv opens pr-1..pr-4 by alice while dave is inactive, so bob
+// and carol review all four of them.
+func newRebalanceEnv(t *testing.T) *testEnv {
+	t.Helper()
+	e := newTestEnv(t)
+	e.addTeam(t, "backend", "alice", "bob", "carol", "dave")
+	if err := e.repos.Users.SetIsActive(context.Background(), "dave", false); err != nil {
+		t.Fatalf("SetIsActive: %v", err)
+	}
+	for _, prID := range []string{"pr-1", "pr-2", "pr-3", "pr-4"} {
+		e.createPR(t, prID, "alice")
+	}
+	return e
+}
+
+func movedReviews(response *models.ReactivationResponse) []models.MovedReview {
+	moved := make([]models.MovedReview, 0, len(response.MovedReviews))
+	for _, m := range response.MovedReviews {
+		moved = append(moved, *m)
+	}
+	return moved
+}
+
+func TestReactivateUserRebalances(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int
+		capacity  int
+		absent    bool
+		wantMoved []models.MovedReview
+	}{
+		{
+			name:  "until loads are even",
+			limit: 3,
+			wantMoved: []models.MovedReview{
+				{PullRequestID: "pr-4", FromUserID: "bob", Reason: models.ReactivationRebalanced},
+				{PullRequestID: "pr-3", FromUserID: "carol", Reason: models.ReactivationRebalanced},
+			},
+		},
+		{
+			name:  "up to the limit",
+			limit: 1,
+			wantMoved: []models.MovedReview{
+				{PullRequestID: "pr-4", FromUserID: "bob", Reason: models.ReactivationRebalanced},
+			},
+		},
+		{
+			name:      "zero limit",
+			limit:     0,
+			wantMoved: []models.MovedReview{},
+		},
+		{
+			name:     "up to capacity",
+			limit:    3,
+			capacity: 1,
+			wantMoved: []models.MovedReview{
+				{PullRequestID: "pr-4", FromUserID: "bob", Reason: models.ReactivationRebalanced},
+			},
+		},
+		{
+			name:      "absent user",
+			limit:     3,
+			absent:    true,
+			wantMoved: []models.MovedReview{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			e := newRebalanceEnv(t)
+			if tt.capacity > 0 {
+				e.setCapacity(t, "dave", tt.capacity)
+			}
+			if tt.absent {
+				_, err := e.availability.AddAvailability(ctx, &models.Availability{
+					UserID:   "dave",
+					StartsAt: time.Now().Add(-time.Hour),
+					EndsAt:   time.Now().Add(time.Hour),
+				})
+				if err != nil {
+					t.Fatalf("AddAvailability: %v", err)
+				}
+			}
+
+			response, err := e.reactivation.ReactivateUser(ctx, &models.ReactivationRequest{UserID: "dave", Limit: tt.limit}, "admin")
+			if err != nil {
+				t.Fatalf("ReactivateUser: %v", err)
+			}
+			if !response.User.IsActive {
+				t.Error("dave is not active")
+			}
+			if got := movedReviews(response); !reflect.DeepEqual(got, tt.wantMoved) {
+				t.Errorf("moved = %+v, want %+v", got, tt.wantMoved)
+			}
+
+			wantBefore := map[string]int{"alice": 0, "bob": 4, "carol": 4}
+			if !tt.absent {
+				wantBefore["dave"] = 0
+			}
+			if !reflect.DeepEqual(response.LoadsBefore, wantBefore) {
+				t.Errorf("loads before = %v, want %v", response.LoadsBefore, wantBefore)
+			}
+			if got := response.LoadsAfter["bob"] + response.LoadsAfter["carol"] + response.LoadsAfter["dave"]; got != 8 {
+				t.Errorf("loads after = %v, want the 8 reviews kept", response.LoadsAfter)
+			}
+			if got := e.notifier.count(models.WebhookPRReassigned); got != len(tt.wantMoved) {
+				t.Errorf("%s notifications = %d, want %d", models.WebhookPRReassigned, got, len(tt.wantMoved))
+			}
+			for _, moved := range tt.wantMoved {
+				if pr := e.getPR(t, moved.PullRequestID); !containsUserID(pr.AssignedReviewers, "dave") {
+					t.Errorf("%s reviewers = %v, want dave", moved.PullRequestID, pr.AssignedReviewers)
+				}
+			}
+		})
+	}
+}
+
+func TestReactivateUserRestoresReviews(t *testing.T) {
+	ctx := context.Background()
+	e := newReviewEnv(t)
+	if _, err := e.deactivation.DeactivateUsers(ctx, "backend", []string{"bob"}, "admin"); err != nil {
+		t.Fatalf("DeactivateUsers: %v", err)
+	}
+	if _, err := e.deactivation.ProcessDue(ctx); err != nil {
+		t.Fatalf("ProcessDue: %v", err)
+	}
+	if pr := e.getPR(t, "pr-1"); !sameUserIDs(pr.AssignedReviewers, []string{"dave", "carol"}) {
+		t.Fatalf("reviewers = %v, want bob replaced by dave", pr.AssignedReviewers)
+	}
+
+	response, err := e.reactivation.ReactivateUser(ctx, &models.ReactivationRequest{UserID: "bob", RestoreReviews: true}, "admin")
+	if err != nil {
+		t.Fatalf("ReactivateUser: %v", err)
+	}
+	want := []models.MovedReview{{PullRequestID: "pr-1", FromUserID: "dave", Reason: models.ReactivationRestored}}
+	if got := movedReviews(response); !reflect.DeepEqual(got, want) {
+		t.Errorf("moved = %+v, want %+v", got, want)
+	}
+	if pr := e.getPR(t, "pr-1"); !sameUserIDs(pr.AssignedReviewers, []string{"bob", "carol"}) {
+		t.Errorf("reviewers = %v, want bob back instead of dave", pr.AssignedReviewers)
+	}
+
+	events, err := e.repos.Events.GetByPullRequest(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetByPullRequest: %v", err)
+	}
+	last := events[len(events)-1]
+	if last.EventType != models.EventReviewerReplaced || last.Details != models.EventDetailsReactivation || last.PreviousUserID != "dave" {
+		t.Errorf("last event = %+v, want the reactivation replacement", last)
+	}
+}
+
+func TestReactivateUserInvalid(t *testing.T) {
+	ctx := context.Background()
+	e := newRebalanceEnv(t)
+
+	if _, err := e.reactivation.ReactivateUser(ctx, &models.ReactivationRequest{UserID: "dave", Limit: -1}, "admin"); err != ErrInvalidLimit {
+		t.Errorf("negative limit error = %v, want %v", err, ErrInvalidLimit)
+	}
+	if _, err := e.reactivation.ReactivateUser(ctx, &models.ReactivationRequest{UserID: "nobody"}, "admin"); err == nil {
+		t.Error("unknown user was reactivated")
+	}
+	if user, _ := e.repos.Users.GetByID(ctx, "dave"); user.IsActive {
+		t.Error("dave was activated by a rejected request")
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"pr-reviewer-service/internal/integration/gitlab"
+	"pr-reviewer-service/internal/logging"
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+// ReviewerSyncer records the reviewers of merge requests linked to GitLab and
+// pushes them back. As a Notifier it follows reassignments made by any part of
+// the service, so a replaced reviewer is replaced in the merge request too.
+type ReviewerSyncer struct {
+	mappingRepo repository.UserMappingRepository
+	syncRepo    repository.ReviewerSyncRepository
+	client      gitlab.Client
+}
+
+// NewReviewerSyncer creates the syncer. client may be nil, then syncs stay
+// PENDING until they are pushed with a configured client.
+func NewReviewerSyncer(
+	mappingRepo repository.UserMappingRepository,
+	syncRepo repository.ReviewerSyncRepository,
+	client gitlab.Client,
+) *ReviewerSyncer {
+	return &ReviewerSyncer{
+		mappingRepo: mappingRepo,
+		syncRepo:    syncRepo,
+		client:      client,
+	}
+}
+
+// Record stores sync and pushes it when a client is configured. A failed push
+// is recorded in sync and logged, it does not fail the call.
+func (s *ReviewerSyncer) Record(ctx context.Context, sync *models.ReviewerSync) error {
+	sync.Status = models.SyncPending
+	err := s.syncRepo.Create(ctx, sync)
+	if err != nil {
+		return err
+	}
+
+	if s.client != nil {
+		if err := s.Push(ctx, sync); err != nil {
+			slog.ErrorContext(ctx, "failed to push reviewer sync", "sync_id", sync.SyncID, "error", err)
+		}
+	}
+	return nil
+}
+
+// Notify records a new sync when a reviewer of a merge request that was
+// already synced is replaced. Notifications are sent once the change is
+// committed and not at all for dry runs, so neither records anything that is
+// rolled back.
+func (s *ReviewerSyncer) Notify(ctx context.Context, eventType string, data interface{}) error {
+	reassigned, ok := data.(*models.PRReassigned)
+	if eventType != models.WebhookPRReassigned || !ok {
+		return nil
+	}
+	ctx = logging.With(context.WithoutCancel(ctx), "pull_request_id", reassigned.PR.PullRequestID)
+
+	syncs, err := s.syncRepo.GetByPullRequest(ctx, reassigned.PR.PullRequestID)
+	if err != nil {
+		return fmt.Errorf("failed to get reviewer syncs: %w", err)
+	}
+	if len(syncs) == 0 {
+		// The PR did not come from GitLab.
+		return nil
+	}
+
+	last := syncs[len(syncs)-1]
+	err = s.Record(ctx, &models.ReviewerSync{
+		Provider:        last.Provider,
+		PullRequestID:   last.PullRequestID,
+		ProjectID:       last.ProjectID,
+		MergeRequestIID: last.MergeRequestIID,
+		Reviewers:       reassigned.PR.AssignedReviewers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record reviewer sync: %w", err)
+	}
+	return nil
+}
+
+// Push sends the reviewers that have a GitLab login and stores the outcome
+// in sync. A failed push is not an error, it is recorded as FAILED.
+func (s *ReviewerSyncer) Push(ctx context.Context, sync *models.ReviewerSync) error {
+	if s.client == nil {
+		return ErrGitLabNotConfigured
+	}
+	ctx = logging.With(ctx, "sync_id", sync.SyncID, "pull_request_id", sync.PullRequestID)
+	mappings, err := s.mappingRepo.GetByProvider(ctx, models.ProviderGitLab)
+	if err != nil {
+		return err
+	}
+	logins := make(map[string]string, len(mappings))
+	for _, m := range mappings {
+		logins[m.UserID] = m.Login
+	}
+
+	usernames := make([]string, 0, len(sync.Reviewers))
+	unmapped := make([]string, 0)
+	for _, reviewerID := range sync.Reviewers {
+		if login, ok := logins[reviewerID]; ok {
+			usernames = append(usernames, login)
+		} else {
+			unmapped = append(unmapped, reviewerID)
+		}
+	}
+
+	sync.Error = ""
+	if len(usernames) == 0 {
+		sync.Status = models.SyncFailed
+		sync.Error = "no reviewer has a gitlab login"
+	} else if err := s.client.AssignReviewers(ctx, sync.ProjectID, sync.MergeRequestIID, usernames); err != nil {
+		sync.Status = models.SyncFailed
+		sync.Error = err.Error()
+	} else {
+		now := time.Now()
+		sync.Status = models.SyncPushed
+		sync.PushedAt = &now
+		if len(unmapped) > 0 {
+			sync.Error = "reviewers without gitlab login: " + strings.Join(unmapped, ", ")
+		}
+	}
+	return s.syncRepo.UpdateStatus(ctx, sync)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"pr-reviewer-service/internal/models"
+)
+
+// TestReviewerSyncerFollowsReassignments checks that a replaced reviewer of a
+// merge request opened on GitLab is replaced there too, whichever path
+// replaced them, and that dry runs push nothing.
+func TestReviewerSyncerFollowsReassignments(t *testing.T) {
+	const prID = "platform/api!7"
+	tests := []struct {
+		name     string
+		reassign func(ctx context.Context, e *testEnv, reviewerID string) error
+		wantPush bool
+	}{
+		{
+			name: "manual",
+			reassign: func(ctx context.Context, e *testEnv, reviewerID string) error {
+				_, _, err := e.prs.ReassignReviewer(ctx, prID, reviewerID, "test")
+				return err
+			},
+			wantPush: true,
+		},
+		{
+			name: "deactivation job",
+			reassign: func(ctx context.Context, e *testEnv, reviewerID string) error {
+				if _, err := e.deactivation.DeactivateUsers(ctx, "platform", []string{reviewerID}, "test"); err != nil {
+					return err
+				}
+				_, err := e.deactivation.ProcessDue(ctx)
+				return err
+			},
+			wantPush: true,
+		},
+		{
+			name: "atomic deactivation",
+			reassign: func(ctx context.Context, e *testEnv, reviewerID string) error {
+				_, err := e.deactivation.DeactivateUsersAtomic(ctx, "platform", []string{reviewerID}, "test")
+				return err
+			},
+			wantPush: true,
+		},
+		{
+			name: "reassign dry run",
+			reassign: func(ctx context.Context, e *testEnv, reviewerID string) error {
+				return e.prs.DryRun(ctx, func(dry *PullRequestService) error {
+					_, _, err := dry.ReassignReviewer(ctx, prID, reviewerID, "test")
+					return err
+				})
+			},
+		},
+		{
+			name: "deactivation dry run",
+			reassign: func(ctx context.Context, e *testEnv, reviewerID string) error {
+				_, err := e.deactivation.DeactivateUsersDryRun(ctx, "platform", []string{reviewerID}, "test", true)
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			e := newGitLabEnv(t)
+			if _, err := deliverGitLab(e, fixture(t, "gitlab/mr_open.json")); err != nil {
+				t.Fatalf("open: %v", err)
+			}
+			replaced := e.getPR(t, prID).AssignedReviewers[0]
+
+			if err := tt.reassign(ctx, e, replaced); err != nil {
+				t.Fatalf("reassign: %v", err)
+			}
+
+			syncs, err := e.syncRepo.GetByPullRequest(ctx, prID)
+			if err != nil {
+				t.Fatalf("GetByPullRequest: %v", err)
+			}
+			calls := e.gitlab.Calls()
+			if !tt.wantPush {
+				if len(syncs) != 1 || len(calls) != 1 {
+					t.Fatalf("syncs = %d, pushes = %d; want only the one from open", len(syncs), len(calls))
+				}
+				return
+			}
+
+			if len(syncs) != 2 || len(calls) != 2 {
+				t.Fatalf("syncs = %d, pushes = %d; want a second one for the replacement", len(syncs), len(calls))
+			}
+			pr := e.getPR(t, prID)
+			last := syncs[len(syncs)-1]
+			if !sameUserIDs(last.Reviewers, pr.AssignedReviewers) || last.Status != models.SyncPushed {
+				t.Errorf("last sync = %+v, want PUSHED with %v", last, pr.AssignedReviewers)
+			}
+			if last.ProjectID != 15 || last.MergeRequestIID != 7 || calls[1].MergeRequestIID != 7 {
+				t.Errorf("pushed to %d!%d, want 15!7", last.ProjectID, last.MergeRequestIID)
+			}
+			for _, reviewerID := range last.Reviewers {
+				if reviewerID == replaced {
+					t.Errorf("replaced reviewer %s is still synced", replaced)
+				}
+			}
+		})
+	}
+}
+
+func TestReviewerSyncerIgnoresPRsNotFromGitLab(t *testing.T) {
+	ctx := context.Background()
+	e := newGitLabEnv(t)
+	pr := e.createPR(t, "pr-1", "alice")
+
+	if _, _, err := e.prs.ReassignReviewer(ctx, pr.PullRequestID, pr.AssignedReviewers[0], "test"); err != nil {
+		t.Fatalf("ReassignReviewer: %v", err)
+	}
+	syncs, err := e.syncRepo.GetByPullRequest(ctx, pr.PullRequestID)
+	if err != nil {
+		t.Fatalf("GetByPullRequest: %v", err)
+	}
+	if len(syncs) != 0 || len(e.gitlab.Calls()) != 0 {
+		t.Errorf("syncs = %d, pushes = %d; want none", len(syncs), len(e.gitlab.Calls()))
+	}
+}
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+const (
+	StrategyRandom      = "random"
+	StrategyRoundRobin  = "round_robin"
+	StrategyLeastLoaded = "least_loaded"
+)
+
+var ErrUnknownStrategy = errors.New("unknown reviewer selection strategy")
+
+// ReviewerSelector picks up to count reviewers out of already filtered candidates.
+type ReviewerSelector interface {
+	Name() string
+	Select(ctx context.Context, teamName string, candidates []*models.User, count int) ([]*models.User, error)
+}
+
+// ScoredSelector is implemented by strategies that rank candidates. Lower
+// scores are picked first; explanations report them as the strategy saw them.
+type ScoredSelector interface {
+	Scores(ctx context.Context, teamName string, candidates []*models.User) (map[string]float64, error)
+}
+
+// PreviewSelector is implemented by strategies that keep state between calls.
+// Preview picks like Select without advancing that state.
+type PreviewSelector interface {
+	Preview(ctx context.Context, teamName string, candidates []*models.User, count int) ([]*models.User, error)
+}
+
+type RandomSelector struct {
+	mu         sync.Mutex
+	randSource *rand.Rand
+}
+
+func NewRandomSelector() *RandomSelector {
+	return &RandomSelector{
+		randSource: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (s *RandomSelector) Name() string {
+	return StrategyRandom
+}
+
+func (s *RandomSelector) Select(ctx context.Context, teamName string, candidates []*models.User, count int) ([]*models.User, error) {
+	shuffled := make([]*models.User, len(candidates))
+	copy(shuffled, candidates)
+
+	s.mu.Lock()
+	for i := len(shuffled) - 1; i > 0; i-- {
+		j := s.randSource.Intn(i + 1)
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	}
+	s.mu.Unlock()
+
+	return shuffled[:limitCount(count, len(shuffled))], nil
+}
+
+// RoundRobinSelector walks over the team members in user_id order and keeps
+// a separate cursor for every team.
+type RoundRobinSelector struct {
+	mu      sync.Mutex
+	cursors map[string]int
+}
+
+func NewRoundRobinSelector() *RoundRobinSelector {
+	return &RoundRobinSelector{cursors: make(map[string]int)}
+}
+
+func (s *RoundRobinSelector) Name() string {
+	return StrategyRoundRobin
+}
+
+func (s *RoundRobinSelector) Select(ctx context.Context, teamName string, candidates []*models.User, count int) ([]*models.User, error) {
+	return s.pick(teamName, candidates, count, true), nil
+}
+
+func (s *RoundRobinSelector) Preview(ctx context.Context, teamName string, candidates []*models.User, count int) ([]*models.User, error) {
+	return s.pick(teamName, candidates, count, false), nil
+}
+
+// Scores is the distance of every candidate from the team cursor.
+func (s *RoundRobinSelector) Scores(ctx context.Context, teamName string, candidates []*models.User) (map[string]float64, error) {
+	ordered := orderByUserID(candidates)
+	scores := make(map[string]float64, len(ordered))
+	if len(ordered) == 0 {
+		return scores, nil
+	}
+
+	s.mu.Lock()
+	start := s.cursors[teamName] % len(ordered)
+	s.mu.Unlock()
+
+	for i, candidate := range ordered {
+		scores[candidate.UserID] = float64((i - start + len(ordered)) % len(ordered))
+	}
+	return scores, nil
+}
+
+func (s *RoundRobinSelector) pick(teamName string, candidates []*models.User, count int, advance bool) []*models.User {
+	count = limitCount(count, len(candidates))
+	if count == 0 {
+		return nil
+	}
+
+	ordered := orderByUserID(candidates)
+
+	s.mu.Lock()
+	start := s.cursors[teamName] % len(ordered)
+	if advance {
+		s.cursors[teamName] = start + count
+	}
+	s.mu.Unlock()
+
+	selected := make([]*models.User, 0, count)
+	for i := 0; i < count; i++ {
+		selected = append(selected, ordered[(start+i)%len(ordered)])
+	}
+	return selected
+}
+
+// fork copies the cursors, so picks made by the copy do not move the original.
+func (s *RoundRobinSelector) fork() *RoundRobinSelector {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	forked := NewRoundRobinSelector()
+	for teamName, cursor := range s.cursors {
+		forked.cursors[teamName] = cursor
+	}
+	return forked
+}
+
+func orderByUserID(candidates []*models.User) []*models.User {
+	ordered := make([]*models.User, len(candidates))
+	copy(ordered, candidates)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].UserID < ordered[j].UserID
+	})
+	return ordered
+}
+
+// LeastLoadedSelector prefers candidates with the smallest number of OPEN PRs
+// under review. Candidates with equal load are ordered randomly.
+type LeastLoadedSelector struct {
+	userRepo repository.UserRepository
+	random   *RandomSelector
+}
+
+func NewLeastLoadedSelector(userRepo repository.UserRepository) *LeastLoadedSelector {
+	return &LeastLoadedSelector{
+		userRepo: userRepo,
+		random:   NewRandomSelector(),
+	}
+}
+
+func (s *LeastLoadedSelector) Name() string {
+	return StrategyLeastLoaded
+}
+
+func (s *LeastLoadedSelector) Select(ctx context.Context, teamName string, candidates []*models.User, count int) ([]*models.User, error) {
+	loads, err := s.Scores(ctx, teamName, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered, err := s.random.Select(ctx, teamName, candidates, len(candidates))
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return loads[ordered[i].UserID] < loads[ordered[j].UserID]
+	})
+
+	return ordered[:limitCount(count, len(ordered))], nil
+}
+
+// Scores is the number of OPEN PRs every candidate reviews.
+func (s *LeastLoadedSelector) Scores(ctx context.Context, teamName string, candidates []*models.User) (map[string]float64, error) {
+	userIDs := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		userIDs[i] = candidate.UserID
+	}
+
+	counts, err := s.userRepo.GetOpenReviewCounts(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[string]float64, len(candidates))
+	for _, userID := range userIDs {
+		scores[userID] = float64(counts[userID])
+	}
+	return scores, nil
+}
+
+func limitCount(count, available int) int {
+	if count > available {
+		return available
+	}
+	if count < 0 {
+		return 0
+	}
+	return count
+}
+
+// SelectorRegistry resolves the selection strategy configured for a team.
+type SelectorRegistry struct {
+	defaultSelector ReviewerSelector
+	teamSelectors   map[string]ReviewerSelector
+}
+
+func NewSelectorRegistry(
+	defaultStrategy string,
+	teamStrategies map[string]string,
+	userRepo repository.UserRepository,
+) (*SelectorRegistry, error) {
+	selectors := map[string]ReviewerSelector{
+		StrategyRandom:      NewRandomSelector(),
+		StrategyRoundRobin:  NewRoundRobinSelector(),
+		StrategyLeastLoaded: NewLeastLoadedSelector(userRepo),
+	}
+
+	if defaultStrategy == "" {
+		defaultStrategy = StrategyRandom
+	}
+	defaultSelector, ok := selectors[defaultStrategy]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStrategy, defaultStrategy)
+	}
+
+	registry := &SelectorRegistry{
+		defaultSelector: defaultSelector,
+		teamSelectors:   make(map[string]ReviewerSelector),
+	}
+	for teamName, strategy := range teamStrategies {
+		selector, ok := selectors[strategy]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q for team %q", ErrUnknownStrategy, strategy, teamName)
+		}
+		registry.teamSelectors[teamName] = selector
+	}
+	return registry, nil
+}
+
+func (r *SelectorRegistry) ForTeam(teamName string) ReviewerSelector {
+	if selector, ok := r.teamSelectors[teamName]; ok {
+		return selector
+	}
+	return r.defaultSelector
+}
+
+// fork returns a registry with the same strategies per team that reads loads
+// through userRepo. With isolated set it starts from a copy of the round-robin
+// cursors, so picks made through the fork leave the registry untouched.
+func (r *SelectorRegistry) fork(userRepo repository.UserRepository, isolated bool) *SelectorRegistry {
+	forked := make(map[ReviewerSelector]ReviewerSelector)
+	forkSelector := func(selector ReviewerSelector) ReviewerSelector {
+		if f, ok := forked[selector]; ok {
+			return f
+		}
+		f := selector
+		switch selector := selector.(type) {
+		case *RoundRobinSelector:
+			if isolated {
+				f = selector.fork()
+			}
+		case *LeastLoadedSelector:
+			f = NewLeastLoadedSelector(userRepo)
+		}
+		forked[selector] = f
+		return f
+	}
+
+	registry := &SelectorRegistry{
+		defaultSelector: forkSelector(r.defaultSelector),
+		teamSelectors:   make(map[string]ReviewerSelector, len(r.teamSelectors)),
+	}
+	for teamName, selector := range r.teamSelectors {
+		registry.teamSelectors[teamName] = forkSelector(selector)
+	}
+	return registry
+}
+
+// ParseTeamStrategies parses "team=strategy" pairs separated by commas,
+// e.g. "backend=round_robin,platform=least_loaded".
+func ParseTeamStrategies(value string) (map[string]string, error) {
+	strategies := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		teamName, strategy, ok := strings.Cut(pair, "=")
+		teamName = strings.TrimSpace(teamName)
+		strategy = strings.TrimSpace(strategy)
+		if !ok || teamName == "" || strategy == "" {
+			return nil, fmt.Errorf("invalid team strategy %q, expected team=strategy", pair)
+		}
+		strategies[teamName] = strategy
+	}
+	return strategies, nil
+}
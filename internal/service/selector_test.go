@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+func testUsers(userIDs ...string) []*models.User {
+	users := make([]*models.User, len(userIDs))
+	for i, userID := range userIDs {
+		users[i] = &models.User{UserID: userID, IsActive: true}
+	}
+	return users
+}
+
+func selectedIDs(users []*models.User) []string {
+	userIDs := make([]string, len(users))
+	for i, user := range users {
+		userIDs[i] = user.UserID
+	}
+	return userIDs
+}
+
+func TestRoundRobinSelector(t *testing.T) {
+	ctx := context.Background()
+	s := NewRoundRobinSelector()
+	// The candidates are walked in user_id order whatever order they come in.
+	candidates := testUsers("dave", "bob", "carol")
+
+	steps := []struct {
+		teamName string
+		count    int
+		want     []string
+	}{
+		{"backend", 2, []string{"bob", "carol"}},
+		{"backend", 2, []string{"dave", "bob"}},
+		{"frontend", 1, []string{"bob"}},
+		{"backend", 1, []string{"carol"}},
+		{"backend", 5, []string{"dave", "bob", "carol"}},
+		{"frontend", 1, []string{"carol"}},
+	}
+	for i, step := range steps {
+		selected, err := s.Select(ctx, step.teamName, candidates, step.count)
+		if err != nil {
+			t.Fatalf("step %d: Select: %v", i, err)
+		}
+		if got := selectedIDs(selected); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("step %d: %s picked %v, want %v", i, step.teamName, got, step.want)
+		}
+	}
+}
+
+func TestRoundRobinSelectorChangingCandidates(t *testing.T) {
+	ctx := context.Background()
+	s := NewRoundRobinSelector()
+
+	if _, err := s.Select(ctx, "backend", testUsers("bob", "carol", "dave"), 2); err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	// The cursor wraps around a shorter list instead of running past it.
+	selected, err := s.Select(ctx, "backend", testUsers("bob", "carol"), 1)
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	if got := selectedIDs(selected); !reflect.DeepEqual(got, []string{"bob"}) {
+		t.Errorf("picked %v, want [bob]", got)
+	}
+
+	if selected, err := s.Select(ctx, "backend", nil, 2); err != nil || len(selected) != 0 {
+		t.Errorf("no candidates: picked %v, %v, want none", selected, err)
+	}
+}
+
+func TestRoundRobinSelectorPreview(t *testing.T) {
+	ctx := context.Background()
+	s := NewRoundRobinSelector()
+	candidates := testUsers("bob", "carol", "dave")
+	if _, err := s.Select(ctx, "backend", candidates, 1); err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+
+	scores, err := s.Scores(ctx, "backend", candidates)
+	if err != nil {
+		t.Fatalf("Scores: %v", err)
+	}
+	if want := map[string]float64{"carol": 0, "dave": 1, "bob": 2}; !reflect.DeepEqual(scores, want) {
+		t.Errorf("scores = %v, want %v", scores, want)
+	}
+
+	for i := 0; i < 2; i++ {
+		preview, err := s.Preview(ctx, "backend", candidates, 1)
+		if err != nil {
+			t.Fatalf("Preview: %v", err)
+		}
+		if got := selectedIDs(preview); !reflect.DeepEqual(got, []string{"carol"}) {
+			t.Errorf("preview %d picked %v, want [carol]", i, got)
+		}
+	}
+	selected, err := s.Select(ctx, "backend", candidates, 1)
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	if got := selectedIDs(selected); !reflect.DeepEqual(got, []string{"carol"}) {
+		t.Errorf("picked %v after previews, want [carol]", got)
+	}
+}
+
+func TestSelectorRegistry(t *testing.T) {
+	registry, err := NewSelectorRegistry(StrategyRoundRobin, map[string]string{
+		"backend":  StrategyLeastLoaded,
+		"platform": StrategyRandom,
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewSelectorRegistry: %v", err)
+	}
+
+	for teamName, want := range map[string]string{
+		"backend":  StrategyLeastLoaded,
+		"platform": StrategyRandom,
+		"frontend": StrategyRoundRobin,
+	} {
+		if got := registry.ForTeam(teamName).Name(); got != want {
+			t.Errorf("ForTeam(%s) = %s, want %s", teamName, got, want)
+		}
+	}
+
+	// Teams sharing a strategy share its state, such as the round-robin cursors.
+	if registry.ForTeam("frontend") != registry.ForTeam("mobile") {
+		t.Error("teams on the default strategy got different selectors")
+	}
+
+	registry, err = NewSelectorRegistry("", nil, nil)
+	if err != nil {
+		t.Fatalf("NewSelectorRegistry: %v", err)
+	}
+	if got := registry.ForTeam("backend").Name(); got != StrategyRandom {
+		t.Errorf("default strategy = %s, want %s", got, StrategyRandom)
+	}
+}
+
+func TestSelectorRegistryUnknownStrategy(t *testing.T) {
+	if _, err := NewSelectorRegistry("fastest", nil, nil); !errors.Is(err, ErrUnknownStrategy) {
+		t.Errorf("unknown default error = %v, want %v", err, ErrUnknownStrategy)
+	}
+	_, err := NewSelectorRegistry(StrategyRandom, map[string]string{"backend": "fastest"}, nil)
+	if !errors.Is(err, ErrUnknownStrategy) {
+		t.Errorf("unknown team strategy error = %v, want %v", err, ErrUnknownStrategy)
+	}
+}
+
+func TestParseTeamStrategies(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{value: "", want: map[string]string{}},
+		{
+			value: " backend = round_robin, platform=least_loaded ,",
+			want:  map[string]string{"backend": StrategyRoundRobin, "platform": StrategyLeastLoaded},
+		},
+		{value: "backend", wantErr: true},
+		{value: "backend=", wantErr: true},
+		{value: "=random", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTeamStrategies(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTeamStrategies(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseTeamStrategies(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+// openReviewCounts serves the loads the least-loaded strategy ranks by.
+type openReviewCounts struct {
+	repository.UserRepository
+	counts map[string]int
+}
+
+func (r *openReviewCounts) GetOpenReviewCounts(ctx context.Context, userIDs []string) (map[string]int, error) {
+	counts := make(map[string]int, len(userIDs))
+	for _, userID := range userIDs {
+		if count, ok := r.counts[userID]; ok {
+			counts[userID] = count
+		}
+	}
+	return counts, nil
+}
+
+func TestLeastLoadedSelector(t *testing.T) {
+	ctx := context.Background()
+	s := NewLeastLoadedSelector(&openReviewCounts{counts: map[string]int{"bob": 3, "carol": 1, "erin": 2}})
+	candidates := testUsers("bob", "carol", "dave", "erin")
+
+	scores, err := s.Scores(ctx, "backend", candidates)
+	if err != nil {
+		t.Fatalf("Scores: %v", err)
+	}
+	if want := map[string]float64{"bob": 3, "carol": 1, "dave": 0, "erin": 2}; !reflect.DeepEqual(scores, want) {
+		t.Errorf("scores = %v, want %v", scores, want)
+	}
+
+	tests := []struct {
+		count int
+		want  []string
+	}{
+		{1, []string{"dave"}},
+		{2, []string{"dave", "carol"}},
+		{4, []string{"dave", "carol", "erin", "bob"}},
+		{9, []string{"dave", "carol", "erin", "bob"}},
+	}
+	for _, tt := range tests {
+		selected, err := s.Select(ctx, "backend", candidates, tt.count)
+		if err != nil {
+			t.Fatalf("Select: %v", err)
+		}
+		if got := selectedIDs(selected); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Select(%d) = %v, want %v", tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestLeastLoadedSelectorTies(t *testing.T) {
+	ctx := context.Background()
+	s := NewLeastLoadedSelector(&openReviewCounts{counts: map[string]int{"bob": 1, "carol": 1, "dave": 1, "erin": 2}})
+	s.random = &RandomSelector{randSource: rand.New(rand.NewSource(1))}
+	candidates := testUsers("bob", "carol", "dave", "erin")
+
+	// Ties are broken randomly, so every tied candidate gets its turn and the
+	// busier one never does.
+	picked := make(map[string]int)
+	for i := 0; i < 100; i++ {
+		selected, err := s.Select(ctx, "backend", candidates, 2)
+		if err != nil {
+			t.Fatalf("Select: %v", err)
+		}
+		for _, user := range selected {
+			picked[user.UserID]++
+		}
+	}
+	for _, userID := range []string{"bob", "carol", "dave"} {
+		if picked[userID] == 0 {
+			t.Errorf("%s was never picked among the tied candidates: %v", userID, picked)
+		}
+	}
+	if picked["erin"] != 0 {
+		t.Errorf("erin was picked %d times over less loaded candidates", picked["erin"])
+	}
+}
+
+// TestLeastLoadedAssignment runs the strategy through CreatePR, where the load
+// comes from the OPEN PRs in the store.
+func TestLeastLoadedAssignment(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t)
+	selectors, err := NewSelectorRegistry(StrategyRoundRobin, map[string]string{"backend": StrategyLeastLoaded}, e.repos.Users)
+	if err != nil {
+		t.Fatalf("NewSelectorRegistry: %v", err)
+	}
+	e.prs.selectors = selectors
+	e.addTeam(t, "backend", "alice", "bob", "carol", "dave")
+	e.updateSettings(t, "backend", func(settings *models.TeamSettings) {
+		settings.ReviewerCount = 1
+	})
+
+	// Every PR goes to whoever reviews the fewest open PRs at the time.
+	loads := map[string]int{}
+	for _, prID := range []string{"pr-1", "pr-2", "pr-3", "pr-4", "pr-5", "pr-6"} {
+		pr := e.createPR(t, prID, "alice")
+		reviewerID := pr.AssignedReviewers[0]
+		for _, userID := range []string{"bob", "carol", "dave"} {
+			if loads[userID] < loads[reviewerID] {
+				t.Fatalf("%s went to %s with loads %v", prID, reviewerID, loads)
+			}
+		}
+		loads[reviewerID]++
+	}
+	if want := map[string]int{"bob": 2, "carol": 2, "dave": 2}; !reflect.DeepEqual(loads, want) {
+		t.Errorf("loads = %v, want %v", loads, want)
+	}
+
+	// Merged PRs no longer count towards the load.
+	for _, prID := range []string{"pr-1", "pr-2", "pr-3", "pr-4", "pr-5", "pr-6"} {
+		if pr := e.getPR(t, prID); pr.AssignedReviewers[0] == "carol" {
+			if _, err := e.prs.MergePR(ctx, prID, "alice"); err != nil {
+				t.Fatalf("MergePR(%s): %v", prID, err)
+			}
+		}
+	}
+	if pr := e.createPR(t, "pr-7", "alice"); pr.AssignedReviewers[0] != "carol" {
+		t.Errorf("pr-7 reviewers = %v, want carol without open reviews", pr.AssignedReviewers)
+	}
+}
@@ -1,19 +1,164 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
 	"pr-reviewer-service/internal/models"
 	"pr-reviewer-service/internal/repository"
 )
 
+var ErrInvalidPeriod = errors.New("from must be before to")
+
+const week = 7 * 24 * time.Hour
+
 type StatsService struct {
-	userRepo *repository.UserRepository
+	userRepo repository.UserRepository
+	teamRepo repository.TeamRepository
+	prRepo   repository.PullRequestRepository
+}
+
+func NewStatsService(
+	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+	prRepo repository.PullRequestRepository,
+) *StatsService {
+	return &StatsService{
+		userRepo: userRepo,
+		teamRepo: teamRepo,
+		prRepo:   prRepo,
+	}
+}
+
+// GetStatistics returns the stats of every user matching filter and of their
+// teams. A zero filter.From means since the beginning and a zero filter.To
+// means now.
+func (s *StatsService) GetStatistics(ctx context.Context, filter models.StatsFilter) (*models.StatsResponse, error) {
+	if filter.To.IsZero() {
+		filter.To = time.Now()
+	}
+	if !filter.From.IsZero() && !filter.From.Before(filter.To) {
+		return nil, ErrInvalidPeriod
+	}
+	if filter.TeamName != "" {
+		if _, err := s.teamRepo.GetByName(ctx, filter.TeamName); err != nil {
+			if err == repository.ErrTeamNotFound {
+				return nil, ErrTeamNotFound
+			}
+			return nil, err
+		}
+	}
+
+	users, err := s.userRepo.GetAllUsersStats(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := s.prRepo.GetMergedPRs(ctx, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.StatsResponse{
+		To:         filter.To,
+		Teams:      make([]*models.TeamStats, 0),
+		Statistics: users,
+	}
+	if response.Statistics == nil {
+		response.Statistics = make([]*models.UserStats, 0)
+	}
+
+	// Without from, reviews per week are counted since the first review so
+	// that the rate does not depend on how long the service has been running.
+	if !filter.From.IsZero() {
+		from := filter.From
+		response.From = &from
+	}
+	for _, user := range users {
+		if response.From == nil || (user.FirstReviewAt != nil && user.FirstReviewAt.Before(*response.From)) {
+			response.From = user.FirstReviewAt
+		}
+	}
+	// Periods shorter than a week count as a whole week.
+	weeks := 1.0
+	if response.From != nil {
+		weeks = math.Max(filter.To.Sub(*response.From).Hours()/week.Hours(), 1)
+	}
+
+	teams := make(map[string]*models.TeamStats)
+	teamOf := make(map[string]string, len(users))
+	for _, user := range users {
+		mergeTimes := make([]time.Duration, 0)
+		for _, pr := range merged {
+			if containsUserID(pr.AssignedReviewers, user.UserID) && pr.CreatedAt != nil {
+				mergeTimes = append(mergeTimes, pr.MergedAt.Sub(*pr.CreatedAt))
+			}
+		}
+		user.MedianTimeToMergeSeconds = medianSeconds(mergeTimes)
+		user.ReviewsPerWeek = perWeek(user.ReviewCount, weeks)
+
+		team, ok := teams[user.TeamName]
+		if !ok {
+			team = &models.TeamStats{TeamName: user.TeamName}
+			teams[user.TeamName] = team
+			response.Teams = append(response.Teams, team)
+		}
+		teamOf[user.UserID] = user.TeamName
+		team.MemberCount++
+		if user.IsActive {
+			team.ActiveMemberCount++
+		}
+		team.AssignedAsReviewerCount += user.AssignedAsReviewerCount
+		team.AuthoredPRCount += user.AuthoredPRCount
+		team.OpenReviewCount += user.OpenReviewCount
+		team.ReviewCount += user.ReviewCount
+	}
+
+	mergeTimes := make(map[string][]time.Duration)
+	for _, pr := range merged {
+		if teamName, ok := teamOf[pr.AuthorID]; ok && pr.CreatedAt != nil {
+			mergeTimes[teamName] = append(mergeTimes[teamName], pr.MergedAt.Sub(*pr.CreatedAt))
+		}
+	}
+	for _, team := range response.Teams {
+		team.MergedPRCount = len(mergeTimes[team.TeamName])
+		team.MedianTimeToMergeSeconds = medianSeconds(mergeTimes[team.TeamName])
+		team.ReviewsPerWeek = perWeek(team.ReviewCount, weeks)
+		if team.ActiveMemberCount > 0 {
+			team.AvgOpenReviews = round2(float64(team.OpenReviewCount) / float64(team.ActiveMemberCount))
+		}
+	}
+	sort.Slice(response.Teams, func(i, j int) bool {
+		return response.Teams[i].TeamName < response.Teams[j].TeamName
+	})
+
+	return response, nil
 }
 
-func NewStatsService(userRepo *repository.UserRepository) *StatsService {
-	return &StatsService{userRepo: userRepo}
+// medianSeconds truncates to whole seconds and is nil without durations.
+func medianSeconds(durations []time.Duration) *int64 {
+	if len(durations) == 0 {
+		return nil
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	middle := len(sorted) / 2
+	median := sorted[middle]
+	if len(sorted)%2 == 0 {
+		median = (sorted[middle-1] + sorted[middle]) / 2
+	}
+	seconds := int64(median / time.Second)
+	return &seconds
 }
 
-func (s *StatsService) GetStatistics() ([]*models.UserStats, error) {
-	return s.userRepo.GetAllUsersStats()
+func perWeek(count int, weeks float64) float64 {
+	return round2(float64(count) / weeks)
 }
 
+// round2 rounds value to two decimal places.
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
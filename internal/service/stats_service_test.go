@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+// fixedMergeTimes reports merged PRs as if each took the given time to merge,
+// since the memory store stamps them with the current time.
+type fixedMergeTimes struct {
+	repository.PullRequestRepository
+	took map[string]time.Duration
+}
+
+func (r *fixedMergeTimes) GetMergedPRs(ctx context.Context, from, to time.Time) ([]*models.PullRequest, error) {
+	prs, err := r.PullRequestRepository.GetMergedPRs(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, pr := range prs {
+		createdAt := pr.MergedAt.Add(-r.took[pr.PullRequestID])
+		pr.CreatedAt = &createdAt
+	}
+	return prs, nil
+}
+
+// newStatsEnv has backend alice, bob and carol and frontend erin and frank.
+// pr-1 by alice and pr-2 by bob are merged after one and three hours, pr-3 by
+// erin is still open.
+func newStatsEnv(t *testing.T) (*testEnv, *StatsService) {
+	t.Helper()
+	ctx := context.Background()
+	e := newTestEnv(t)
+	e.addTeam(t, "backend", "alice", "bob", "carol")
+	e.addTeam(t, "frontend", "erin", "frank")
+
+	for _, pr := range []struct{ prID, authorID, reviewerID string }{
+		{"pr-1", "alice", "bob"},
+		{"pr-2", "bob", "carol"},
+	} {
+		e.createPR(t, pr.prID, pr.authorID)
+		if _, err := e.prs.SubmitReview(ctx, pr.prID, pr.reviewerID, models.VerdictApproved); err != nil {
+			t.Fatalf("SubmitReview(%s): %v", pr.prID, err)
+		}
+		if _, err := e.prs.MergePR(ctx, pr.prID, pr.authorID); err != nil {
+			t.Fatalf("MergePR(%s): %v", pr.prID, err)
+		}
+	}
+	e.createPR(t, "pr-3", "erin")
+
+	prRepo := &fixedMergeTimes{
+		PullRequestRepository: e.repos.PullRequests,
+		took:                  map[string]time.Duration{"pr-1": time.Hour, "pr-2": 3 * time.Hour},
+	}
+	return e, NewStatsService(e.repos.Users, e.repos.Teams, prRepo)
+}
+
+func userStats(response *models.StatsResponse) map[string]*models.UserStats {
+	stats := make(map[string]*models.UserStats, len(response.Statistics))
+	for _, user := range response.Statistics {
+		stats[user.UserID] = user
+	}
+	return stats
+}
+
+func seconds(d time.Duration) *int64 {
+	s := int64(d / time.Second)
+	return &s
+}
+
+func sameSeconds(got, want *int64) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+	return *got == *want
+}
+
+func TestGetStatistics(t *testing.T) {
+	ctx := context.Background()
+	_, stats := newStatsEnv(t)
+
+	response, err := stats.GetStatistics(ctx, models.StatsFilter{})
+	if err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+
+	users := userStats(response)
+	wantUsers := []struct {
+		userID             string
+		assigned, authored int
+		open, reviews      int
+		medianTimeToMerge  *int64
+	}{
+		{userID: "alice", assigned: 1, authored: 1, medianTimeToMerge: seconds(3 * time.Hour)},
+		{userID: "bob", assigned: 1, authored: 1, reviews: 1, medianTimeToMerge: seconds(time.Hour)},
+		{userID: "carol", assigned: 2, reviews: 1, medianTimeToMerge: seconds(2 * time.Hour)},
+		{userID: "erin", authored: 1},
+		{userID: "frank", assigned: 1, open: 1},
+	}
+	if len(users) != len(wantUsers) {
+		t.Fatalf("statistics = %d users, want %d", len(users), len(wantUsers))
+	}
+	for _, want := range wantUsers {
+		got := users[want.userID]
+		if got.AssignedAsReviewerCount != want.assigned || got.AuthoredPRCount != want.authored ||
+			got.OpenReviewCount != want.open || got.ReviewCount != want.reviews {
+			t.Errorf("%s: assigned %d, authored %d, open %d, reviews %d, want %d, %d, %d, %d", want.userID,
+				got.AssignedAsReviewerCount, got.AuthoredPRCount, got.OpenReviewCount, got.ReviewCount,
+				want.assigned, want.authored, want.open, want.reviews)
+		}
+		if !sameSeconds(got.MedianTimeToMergeSeconds, want.medianTimeToMerge) {
+			t.Errorf("%s median time to merge = %v, want %v", want.userID, got.MedianTimeToMergeSeconds, want.medianTimeToMerge)
+		}
+	}
+
+	if len(response.Teams) != 2 {
+		t.Fatalf("teams = %+v, want backend and frontend", response.Teams)
+	}
+	backend, frontend := response.Teams[0], response.Teams[1]
+	if backend.TeamName != "backend" || backend.MemberCount != 3 || backend.AuthoredPRCount != 2 ||
+		backend.ReviewCount != 2 || backend.MergedPRCount != 2 || !sameSeconds(backend.MedianTimeToMergeSeconds, seconds(2*time.Hour)) {
+		t.Errorf("backend = %+v", backend)
+	}
+	if frontend.TeamName != "frontend" || frontend.OpenReviewCount != 1 || frontend.AvgOpenReviews != 0.5 ||
+		frontend.MergedPRCount != 0 || frontend.MedianTimeToMergeSeconds != nil {
+		t.Errorf("frontend = %+v", frontend)
+	}
+}
+
+func TestGetStatisticsFilter(t *testing.T) {
+	ctx := context.Background()
+	e, stats := newStatsEnv(t)
+
+	response, err := stats.GetStatistics(ctx, models.StatsFilter{TeamName: "frontend"})
+	if err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	if len(response.Teams) != 1 || response.Teams[0].TeamName != "frontend" || len(response.Statistics) != 2 {
+		t.Errorf("teams = %+v, statistics = %d users, want frontend only", response.Teams, len(response.Statistics))
+	}
+
+	// Inactive members count towards the team but not its average load.
+	if err := e.repos.Users.SetIsActive(ctx, "erin", false); err != nil {
+		t.Fatalf("SetIsActive: %v", err)
+	}
+	response, err = stats.GetStatistics(ctx, models.StatsFilter{TeamName: "frontend"})
+	if err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	if team := response.Teams[0]; team.MemberCount != 2 || team.ActiveMemberCount != 1 || team.AvgOpenReviews != 1 {
+		t.Errorf("frontend = %+v, want one active member with one open review", team)
+	}
+
+	// The period limits the counts but not the current load.
+	later := models.StatsFilter{From: time.Now().Add(time.Hour), To: time.Now().Add(2 * time.Hour)}
+	response, err = stats.GetStatistics(ctx, later)
+	if err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	frank := userStats(response)["frank"]
+	if frank.AssignedAsReviewerCount != 0 || frank.OpenReviewCount != 1 {
+		t.Errorf("frank = %+v, want no assignments in the period and one open review", frank)
+	}
+	if response.Teams[0].MergedPRCount != 0 {
+		t.Errorf("backend merged = %d, want none in the period", response.Teams[0].MergedPRCount)
+	}
+
+	if _, err := stats.GetStatistics(ctx, models.StatsFilter{TeamName: "nobody"}); err != ErrTeamNotFound {
+		t.Errorf("unknown team error = %v, want %v", err, ErrTeamNotFound)
+	}
+	if _, err := stats.GetStatistics(ctx, models.StatsFilter{From: later.To, To: later.From}); err != ErrInvalidPeriod {
+		t.Errorf("reversed period error = %v, want %v", err, ErrInvalidPeriod)
+	}
+}
+
+func TestGetStatisticsReviewsPerWeek(t *testing.T) {
+	ctx := context.Background()
+	_, stats := newStatsEnv(t)
+
+	tests := []struct {
+		name     string
+		from     time.Time
+		wantFrom bool
+		want     float64
+	}{
+		{name: "since the first review", want: 1},
+		{name: "two weeks", from: time.Now().Add(-2 * week), wantFrom: true, want: 0.5},
+		{name: "shorter than a week", from: time.Now().Add(-time.Hour), wantFrom: true, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := stats.GetStatistics(ctx, models.StatsFilter{From: tt.from})
+			if err != nil {
+				t.Fatalf("GetStatistics: %v", err)
+			}
+			if response.From == nil || tt.wantFrom && !response.From.Equal(tt.from) {
+				t.Errorf("from = %v, want %v", response.From, tt.from)
+			}
+			if got := userStats(response)["bob"].ReviewsPerWeek; got != tt.want {
+				t.Errorf("bob reviews per week = %v, want %v", got, tt.want)
+			}
+			if got := response.Teams[0].ReviewsPerWeek; got != 2*tt.want {
+				t.Errorf("backend reviews per week = %v, want %v", got, 2*tt.want)
+			}
+		})
+	}
+}
+
+func TestMedianSeconds(t *testing.T) {
+	tests := []struct {
+		name      string
+		durations []time.Duration
+		want      *int64
+	}{
+		{name: "none", durations: nil, want: nil},
+		{name: "one", durations: []time.Duration{90 * time.Second}, want: seconds(90 * time.Second)},
+		{name: "odd count unsorted", durations: []time.Duration{5 * time.Hour, time.Hour, 2 * time.Hour}, want: seconds(2 * time.Hour)},
+		{name: "even count", durations: []time.Duration{time.Hour, 4 * time.Hour, 2 * time.Hour, 3 * time.Hour}, want: seconds(150 * time.Minute)},
+		{name: "whole seconds", durations: []time.Duration{1500 * time.Millisecond}, want: seconds(time.Second)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := medianSeconds(tt.durations); !sameSeconds(got, tt.want) {
+				t.Errorf("medianSeconds(%v) = %v, want %v", tt.durations, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPerWeek(t *testing.T) {
+	tests := []struct {
+		count int
+		weeks float64
+		want  float64
+	}{
+		{count: 0, weeks: 1, want: 0},
+		{count: 3, weeks: 1, want: 3},
+		{count: 1, weeks: 3, want: 0.33},
+		{count: 2, weeks: 3, want: 0.67},
+	}
+	for _, tt := range tests {
+		if got := perWeek(tt.count, tt.weeks); got != tt.want {
+			t.Errorf("perWeek(%d, %v) = %v, want %v", tt.count, tt.weeks, got, tt.want)
+		}
+	}
+}
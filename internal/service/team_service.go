@@ -1,23 +1,113 @@
 package service
 
 import (
+	"context"
+	"errors"
+
+	"pr-reviewer-service/internal/codeowners"
 	"pr-reviewer-service/internal/models"
 	"pr-reviewer-service/internal/repository"
 )
 
+var (
+	ErrInvalidSettings      = errors.New("invalid team settings")
+	ErrInvalidFallbackTeams = errors.New("invalid fallback teams")
+)
+
 type TeamService struct {
-	teamRepo *repository.TeamRepository
+	teamRepo repository.TeamRepository
 }
 
-func NewTeamService(teamRepo *repository.TeamRepository) *TeamService {
+func NewTeamService(teamRepo repository.TeamRepository) *TeamService {
 	return &TeamService{teamRepo: teamRepo}
 }
 
-func (s *TeamService) CreateTeam(team *models.Team) error {
-	return s.teamRepo.Create(team)
+func (s *TeamService) CreateTeam(ctx context.Context, team *models.Team) error {
+	return s.teamRepo.Create(ctx, team)
+}
+
+func (s *TeamService) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
+	return s.teamRepo.GetByName(ctx, teamName)
+}
+
+func (s *TeamService) GetSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	return s.teamRepo.GetSettings(ctx, teamName)
+}
+
+func (s *TeamService) UpdateSettings(ctx context.Context, settings *models.TeamSettings) (*models.TeamSettings, error) {
+	if settings.ReviewerCount < 0 || settings.ReviewerCount > models.MaxReviewerCount {
+		return nil, ErrInvalidSettings
+	}
+	if settings.MinReviewers < 0 || settings.MinReviewers > settings.ReviewerCount {
+		return nil, ErrInvalidSettings
+	}
+	if settings.CapacityPolicy != models.CapacityPolicyAssignFewer && settings.CapacityPolicy != models.CapacityPolicyFail {
+		return nil, ErrInvalidSettings
+	}
+	if settings.RequiredApprovals < 0 || settings.RequiredApprovals > settings.ReviewerCount {
+		return nil, ErrInvalidSettings
+	}
+	if err := s.validateFallbackTeams(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	err := s.teamRepo.UpdateSettings(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+	return s.teamRepo.GetSettings(ctx, settings.TeamName)
+}
+
+// validateFallbackTeams checks that fallback teams exist, are listed once and
+// do not include the team itself.
+func (s *TeamService) validateFallbackTeams(ctx context.Context, settings *models.TeamSettings) error {
+	if settings.FallbackTeams == nil {
+		settings.FallbackTeams = []string{}
+	}
+
+	seen := make(map[string]bool, len(settings.FallbackTeams))
+	for _, teamName := range settings.FallbackTeams {
+		if teamName == settings.TeamName || seen[teamName] {
+			return ErrInvalidFallbackTeams
+		}
+		seen[teamName] = true
+
+		_, err := s.teamRepo.GetSettings(ctx, teamName)
+		if err == repository.ErrTeamNotFound {
+			return ErrInvalidFallbackTeams
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (s *TeamService) GetTeam(teamName string) (*models.Team, error) {
-	return s.teamRepo.GetByName(teamName)
+// GetCodeOwners returns the uploaded CODEOWNERS file with its parsed rules.
+func (s *TeamService) GetCodeOwners(ctx context.Context, teamName string) (*models.TeamCodeOwners, *codeowners.Ruleset, error) {
+	codeOwners, err := s.teamRepo.GetCodeOwners(ctx, teamName)
+	if err != nil {
+		return nil, nil, err
+	}
+	ruleset, err := codeowners.Parse(codeOwners.Content)
+	if err != nil {
+		return nil, nil, err
+	}
+	return codeOwners, ruleset, nil
 }
 
+// SetCodeOwners replaces the team's CODEOWNERS file. The content is rejected
+// with a *codeowners.SyntaxError when it does not parse.
+func (s *TeamService) SetCodeOwners(ctx context.Context, teamName, content string) (*models.TeamCodeOwners, *codeowners.Ruleset, error) {
+	ruleset, err := codeowners.Parse(content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	codeOwners := &models.TeamCodeOwners{TeamName: teamName, Content: content}
+	err = s.teamRepo.SetCodeOwners(ctx, codeOwners)
+	if err != nil {
+		return nil, nil, err
+	}
+	return codeOwners, ruleset, nil
+}
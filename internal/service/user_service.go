@@ -1,23 +1,45 @@
 package service
 
 import (
+	"context"
+	"errors"
+
+	"pr-reviewer-service/internal/logging"
 	"pr-reviewer-service/internal/models"
 	"pr-reviewer-service/internal/repository"
 )
 
+var (
+	ErrInvalidCapacity = errors.New("max_open_reviews must not be negative")
+)
+
 type UserService struct {
-	userRepo *repository.UserRepository
+	userRepo repository.UserRepository
 }
 
-func NewUserService(userRepo *repository.UserRepository) *UserService {
+func NewUserService(userRepo repository.UserRepository) *UserService {
 	return &UserService{userRepo: userRepo}
 }
 
-func (s *UserService) SetIsActive(userID string, isActive bool) (*models.User, error) {
-	err := s.userRepo.SetIsActive(userID, isActive)
+func (s *UserService) SetIsActive(ctx context.Context, userID string, isActive bool) (*models.User, error) {
+	ctx = logging.With(ctx, "user_id", userID)
+	err := s.userRepo.SetIsActive(ctx, userID, isActive)
 	if err != nil {
 		return nil, err
 	}
-	return s.userRepo.GetByID(userID)
+	return s.userRepo.GetByID(ctx, userID)
 }
 
+
+func (s *UserService) SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) (*models.User, error) {
+	ctx = logging.With(ctx, "user_id", userID)
+	if maxOpenReviews != nil && *maxOpenReviews < 0 {
+		return nil, ErrInvalidCapacity
+	}
+
+	err := s.userRepo.SetMaxOpenReviews(ctx, userID, maxOpenReviews)
+	if err != nil {
+		return nil, err
+	}
+	return s.userRepo.GetByID(ctx, userID)
+}
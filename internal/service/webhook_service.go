@@ -0,0 +1,349 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"pr-reviewer-service/internal/logging"
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository"
+)
+
+var (
+	ErrInvalidWebhookURL   = errors.New("url must be an absolute http(s) URL")
+	ErrWebhookSecretEmpty  = errors.New("secret is required")
+	ErrUnknownWebhookEvent = errors.New("unknown webhook event")
+)
+
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookSignatureHeader = "X-Webhook-Signature-256"
+
+	webhookBatchSize = 50
+	// webhookLease must outlive a single send, otherwise another worker may
+	// claim the delivery while it is still in flight.
+	webhookLease = time.Minute
+)
+
+// Notifier publishes state changes to external subscribers. It is called after
+// the change is made, so an error means the change stands but not everybody was
+// told about it.
+type Notifier interface {
+	Notify(ctx context.Context, eventType string, data interface{}) error
+}
+
+// Notifiers passes every notification to each of its notifiers in turn.
+type Notifiers []Notifier
+
+func (n Notifiers) Notify(ctx context.Context, eventType string, data interface{}) error {
+	var errs []error
+	for _, notifier := range n {
+		if err := notifier.Notify(ctx, eventType, data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// discardNotifier drops notifications about changes that are rolled back.
+type discardNotifier struct{}
+
+func (discardNotifier) Notify(ctx context.Context, eventType string, data interface{}) error {
+	return nil
+}
+
+// deferredNotifier holds notifications until the transaction is committed.
+type deferredNotifier struct {
+	target  Notifier
+	pending []deferredNotification
+}
+
+type deferredNotification struct {
+	ctx       context.Context
+	eventType string
+	data      interface{}
+}
+
+func (n *deferredNotifier) Notify(ctx context.Context, eventType string, data interface{}) error {
+	n.pending = append(n.pending, deferredNotification{ctx: ctx, eventType: eventType, data: data})
+	return nil
+}
+
+// flush ignores cancellation of the request: the change is already committed.
+func (n *deferredNotifier) flush() error {
+	var errs []error
+	for _, notification := range n.pending {
+		err := n.target.Notify(context.WithoutCancel(notification.ctx), notification.eventType, notification.data)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	n.pending = nil
+	return errors.Join(errs...)
+}
+
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 8,
+		BaseBackoff: 10 * time.Second,
+		MaxBackoff:  time.Hour,
+	}
+}
+
+// Backoff returns the delay before the next attempt after the given number of
+// failed attempts: BaseBackoff, doubled every time and capped by MaxBackoff.
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	delay := p.BaseBackoff
+	for i := 1; i < attempts && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return delay
+}
+
+type webhookEnvelope struct {
+	Event      string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+type WebhookService struct {
+	webhookRepo repository.WebhookRepository
+	client      *http.Client
+	policy      RetryPolicy
+	heartbeat   *Heartbeat
+}
+
+func NewWebhookService(
+	webhookRepo repository.WebhookRepository,
+	client *http.Client,
+	policy RetryPolicy,
+) *WebhookService {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		client:      client,
+		policy:      policy,
+		heartbeat:   newHeartbeat("webhook_delivery"),
+	}
+}
+
+func (s *WebhookService) CreateSubscription(ctx context.Context, rawURL string, events []string, secret string) (*models.WebhookSubscription, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, ErrInvalidWebhookURL
+	}
+	if secret == "" {
+		return nil, ErrWebhookSecretEmpty
+	}
+	for _, event := range events {
+		if !models.IsWebhookEvent(event) {
+			return nil, ErrUnknownWebhookEvent
+		}
+	}
+	if events == nil {
+		events = []string{}
+	}
+
+	sub := &models.WebhookSubscription{
+		URL:    rawURL,
+		Events: events,
+		Secret: secret,
+	}
+	err = s.webhookRepo.CreateSubscription(ctx, sub)
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *WebhookService) GetSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	return s.webhookRepo.GetSubscriptions(ctx)
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, subscriptionID int64) error {
+	return s.webhookRepo.DeleteSubscription(ctx, subscriptionID)
+}
+
+func (s *WebhookService) GetDeadDeliveries(ctx context.Context) ([]*models.WebhookDelivery, error) {
+	return s.webhookRepo.GetDeadDeliveries(ctx)
+}
+
+func (s *WebhookService) RetryDelivery(ctx context.Context, deliveryID int64) (*models.WebhookDelivery, error) {
+	return s.webhookRepo.RequeueDelivery(ctx, deliveryID)
+}
+
+// Notify queues a delivery for every matching subscription. Sending happens
+// later in ProcessDue, so a slow receiver never delays the API call. The
+// deliveries are queued even if ctx is cancelled, since the change they
+// report has already been made.
+func (s *WebhookService) Notify(ctx context.Context, eventType string, data interface{}) error {
+	err := s.enqueue(context.WithoutCancel(ctx), eventType, data)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue %s webhooks: %w", eventType, err)
+	}
+	return nil
+}
+
+func (s *WebhookService) enqueue(ctx context.Context, eventType string, data interface{}) error {
+	subs, err := s.webhookRepo.GetSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for _, sub := range subs {
+		if !sub.Accepts(eventType) {
+			continue
+		}
+		if payload == nil {
+			payload, err = json.Marshal(webhookEnvelope{
+				Event:      eventType,
+				OccurredAt: time.Now().UTC(),
+				Data:       data,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		err = s.webhookRepo.EnqueueDelivery(ctx, &models.WebhookDelivery{
+			SubscriptionID: sub.SubscriptionID,
+			EventType:      eventType,
+			Payload:        payload,
+		})
+		if err != nil && err != repository.ErrSubscriptionNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProcessDue sends deliveries whose attempt time has come and returns how
+// many of them were attempted.
+func (s *WebhookService) ProcessDue(ctx context.Context) (int, error) {
+	deliveries, err := s.webhookRepo.ClaimDueDeliveries(ctx, webhookBatchSize, webhookLease)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, d := range deliveries {
+		if err := s.attempt(ctx, d); err != nil {
+			return i, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// Heartbeat reports the progress of Run to the readiness check.
+func (s *WebhookService) Heartbeat() *Heartbeat {
+	return s.heartbeat
+}
+
+// Run sends due deliveries every interval until ctx is done. A batch in
+// progress when ctx is done is finished first.
+func (s *WebhookService) Run(ctx context.Context, interval time.Duration) {
+	work := logging.With(context.WithoutCancel(ctx), "worker", s.heartbeat.Name())
+	defer s.heartbeat.run()()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			done := s.heartbeat.pass()
+			if _, err := s.ProcessDue(work); err != nil {
+				slog.ErrorContext(work, "failed to process webhook deliveries", "error", err)
+			}
+			done()
+		}
+	}
+}
+
+func (s *WebhookService) attempt(ctx context.Context, d *models.WebhookDelivery) error {
+	ctx = logging.With(ctx, "delivery_id", d.DeliveryID, "subscription_id", d.SubscriptionID)
+	sub, err := s.webhookRepo.GetSubscription(ctx, d.SubscriptionID)
+	if err == repository.ErrSubscriptionNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	sendErr := s.send(ctx, sub, d)
+
+	now := time.Now()
+	d.Attempts++
+	if sendErr == nil {
+		d.Status = models.DeliveryDelivered
+		d.LastError = ""
+		d.DeliveredAt = &now
+	} else {
+		d.LastError = sendErr.Error()
+		if d.Attempts >= s.policy.MaxAttempts {
+			d.Status = models.DeliveryDead
+		} else {
+			d.NextAttemptAt = now.Add(s.policy.Backoff(d.Attempts))
+		}
+	}
+
+	err = s.webhookRepo.SaveDeliveryAttempt(ctx, d)
+	if err == repository.ErrDeliveryNotFound {
+		return nil
+	}
+	return err
+}
+
+func (s *WebhookService) send(ctx context.Context, sub *models.WebhookSubscription, d *models.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, d.EventType)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(d.DeliveryID, 10))
+	req.Header.Set(WebhookSignatureHeader, SignPayload(sub.Secret, d.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SignPayload returns the value of the signature header: "sha256=" followed
+// by the hex encoded HMAC-SHA256 of the body keyed with the subscription secret.
+func SignPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
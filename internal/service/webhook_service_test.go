@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository/memory"
+)
+
+// receiver is a webhook subscriber answering with the given statuses in turn
+// and 200 once they run out.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*receivedRequest
+}
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.requests = append(rc.requests, &receivedRequest{header: r.Header.Clone(), body: body})
+	status := http.StatusOK
+	if len(rc.statuses) > 0 {
+		status, rc.statuses = rc.statuses[0], rc.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (rc *receiver) count() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return len(rc.requests)
+}
+
+// retryNow retries failed deliveries right away, so a test can drive every
+// attempt with ProcessDue.
+func retryNow(maxAttempts int) RetryPolicy {
+	return RetryPolicy{MaxAttempts: maxAttempts, BaseBackoff: time.Nanosecond, MaxBackoff: time.Nanosecond}
+}
+
+func newTestWebhookService(t *testing.T, rc *receiver, policy RetryPolicy) (*WebhookService, *models.WebhookSubscription) {
+	t.Helper()
+	server := httptest.NewServer(rc)
+	t.Cleanup(server.Close)
+
+	s := NewWebhookService(memory.NewWebhookRepository(memory.NewStore()), server.Client(), policy)
+	sub, err := s.CreateSubscription(context.Background(), server.URL, []string{models.WebhookPRMerged}, "s3cret")
+	if err != nil {
+		t.Fatalf("CreateSubscription: %v", err)
+	}
+	return s, sub
+}
+
+func TestWebhookDeliveryIsSigned(t *testing.T) {
+	ctx := context.Background()
+	rc := &receiver{}
+	s, sub := newTestWebhookService(t, rc, retryNow(3))
+
+	s.Notify(ctx, models.WebhookPRCreated, map[string]string{"pull_request_id": "pr-1"})
+	s.Notify(ctx, models.WebhookPRMerged, map[string]string{"pull_request_id": "pr-1"})
+	if n, err := s.ProcessDue(ctx); err != nil || n != 1 {
+		t.Fatalf("ProcessDue = %d, %v; want 1 delivery of the subscribed event", n, err)
+	}
+
+	req := rc.requests[0]
+	if got, want := req.header.Get(WebhookSignatureHeader), SignPayload(sub.Secret, req.body); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+	if got := req.header.Get(WebhookEventHeader); got != models.WebhookPRMerged {
+		t.Errorf("event header = %q, want %q", got, models.WebhookPRMerged)
+	}
+	if _, err := strconv.ParseInt(req.header.Get(WebhookDeliveryHeader), 10, 64); err != nil {
+		t.Errorf("delivery header: %v", err)
+	}
+
+	var envelope struct {
+		Event string            `json:"event"`
+		Data  map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(req.body, &envelope); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if envelope.Event != models.WebhookPRMerged || envelope.Data["pull_request_id"] != "pr-1" {
+		t.Errorf("payload = %s", req.body)
+	}
+}
+
+func TestWebhookDeliveryRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		maxAttempts  int
+		wantRequests int
+		wantDead     bool
+	}{
+		{"delivered at once", nil, 3, 1, false},
+		{"delivered after retries", []int{500, 503}, 3, 3, false},
+		{"dead after last attempt", []int{500, 500, 500}, 3, 3, true},
+		{"client error is a failure", []int{404}, 1, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			rc := &receiver{statuses: tt.statuses}
+			s, _ := newTestWebhookService(t, rc, retryNow(tt.maxAttempts))
+
+			s.Notify(ctx, models.WebhookPRMerged, map[string]string{})
+			for i := 0; i < tt.maxAttempts+1; i++ {
+				if _, err := s.ProcessDue(ctx); err != nil {
+					t.Fatalf("ProcessDue: %v", err)
+				}
+			}
+
+			if got := rc.count(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+			dead, err := s.GetDeadDeliveries(ctx)
+			if err != nil {
+				t.Fatalf("GetDeadDeliveries: %v", err)
+			}
+			if got := len(dead) == 1; got != tt.wantDead {
+				t.Fatalf("dead deliveries = %d, want dead %v", len(dead), tt.wantDead)
+			}
+			if !tt.wantDead {
+				return
+			}
+			if dead[0].Attempts != tt.maxAttempts || dead[0].LastError == "" {
+				t.Errorf("dead delivery = %+v", dead[0])
+			}
+
+			// A requeued dead delivery is sent again with a fresh attempt counter.
+			requeued, err := s.RetryDelivery(ctx, dead[0].DeliveryID)
+			if err != nil {
+				t.Fatalf("RetryDelivery: %v", err)
+			}
+			if requeued.Status != models.DeliveryPending || requeued.Attempts != 0 {
+				t.Errorf("requeued delivery = %+v", requeued)
+			}
+			if n, err := s.ProcessDue(ctx); err != nil || n != 1 {
+				t.Fatalf("ProcessDue after requeue = %d, %v", n, err)
+			}
+			if dead, _ := s.GetDeadDeliveries(ctx); len(dead) != 0 {
+				t.Errorf("dead deliveries after requeue = %d, want 0", len(dead))
+			}
+		})
+	}
+}
+
+func TestWebhookDeliveryStopsWithContext(t *testing.T) {
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-block:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(block)
+
+	s := NewWebhookService(memory.NewWebhookRepository(memory.NewStore()), server.Client(), retryNow(2))
+	if _, err := s.CreateSubscription(context.Background(), server.URL, nil, "s3cret"); err != nil {
+		t.Fatalf("CreateSubscription: %v", err)
+	}
+	s.Notify(context.Background(), models.WebhookPRMerged, map[string]string{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.ProcessDue(ctx)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("ProcessDue: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("delivery was not cut off when its context was done")
+	}
+}
+
+func TestWebhookNotifyQueuesAfterCancel(t *testing.T) {
+	rc := &receiver{}
+	s, _ := newTestWebhookService(t, rc, retryNow(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.Notify(ctx, models.WebhookPRMerged, map[string]string{}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if n, err := s.ProcessDue(context.Background()); err != nil || n != 1 {
+		t.Fatalf("ProcessDue = %d, %v; want the delivery queued by the cancelled request", n, err)
+	}
+}
+
+func TestNotifyErrorsReachTheCaller(t *testing.T) {
+	errNotify := errors.New("queue is down")
+	ctx := context.Background()
+
+	t.Run("create", func(t *testing.T) {
+		e := newTestEnv(t)
+		e.addTeam(t, "backend", "alice", "bob")
+		e.notifier.err = errNotify
+
+		if _, err := e.prs.CreatePR(ctx, "pr-1", "pr-1", "alice", false, nil, "test"); !errors.Is(err, errNotify) {
+			t.Fatalf("CreatePR error = %v, want %v", err, errNotify)
+		}
+		e.getPR(t, "pr-1")
+	})
+
+	t.Run("deactivation", func(t *testing.T) {
+		e := newTestEnv(t)
+		e.addTeam(t, "backend", "alice", "bob")
+		e.notifier.err = errNotify
+
+		if _, err := e.deactivation.DeactivateUsersAtomic(ctx, "backend", []string{"bob"}, "admin"); !errors.Is(err, errNotify) {
+			t.Fatalf("DeactivateUsersAtomic error = %v, want %v", err, errNotify)
+		}
+		if user, _ := e.repos.Users.GetByID(ctx, "bob"); user.IsActive {
+			t.Error("bob is active, want the committed deactivation kept")
+		}
+	})
+
+	t.Run("worker", func(t *testing.T) {
+		e := newReviewEnv(t)
+		response, err := e.deactivation.DeactivateUsers(ctx, "backend", []string{"bob"}, "admin")
+		if err != nil {
+			t.Fatalf("DeactivateUsers: %v", err)
+		}
+		e.notifier.err = errNotify
+
+		if _, err := e.deactivation.ProcessDue(ctx); !errors.Is(err, errNotify) {
+			t.Fatalf("ProcessDue error = %v, want %v", err, errNotify)
+		}
+		job, err := e.deactivation.GetJob(ctx, response.Job.JobID)
+		if err != nil {
+			t.Fatalf("GetJob: %v", err)
+		}
+		if job.Status != models.JobCompleted || job.Items[0].Status != models.JobItemDone {
+			t.Errorf("job = %+v, want the replacement saved as DONE", job)
+		}
+	})
+}
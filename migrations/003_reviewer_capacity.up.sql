@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS max_open_reviews INTEGER;
+
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS capacity_policy VARCHAR(20) NOT NULL DEFAULT 'assign_fewer';
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS user_availability (
+    availability_id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    reassign_reviews BOOLEAN NOT NULL DEFAULT false,
+    reviews_reassigned_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_user_availability_user_id ON user_availability(user_id, starts_at, ends_at);
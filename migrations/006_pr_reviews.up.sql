@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS pr_reviews (
+    pull_request_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    verdict VARCHAR(30) NOT NULL,
+    submitted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (pull_request_id, user_id),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(user_id),
+    CHECK (verdict IN ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED'))
+);
+
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS required_approvals INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS pr_events_no_update ON pr_events;
+DROP FUNCTION IF EXISTS pr_events_append_only();
+
+DROP INDEX IF EXISTS idx_pr_events_actor;
+DROP INDEX IF EXISTS idx_pr_events_previous_user_id;
+DROP INDEX IF EXISTS idx_pr_events_user_id;
+DROP INDEX IF EXISTS idx_pr_events_pull_request_id;
+
+DROP TABLE IF EXISTS pr_events;
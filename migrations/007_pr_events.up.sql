@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS pr_events (
+    event_id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    pull_request_id VARCHAR(255),
+    user_id VARCHAR(255),
+    previous_user_id VARCHAR(255),
+    actor VARCHAR(255) NOT NULL,
+    strategy VARCHAR(50),
+    details VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_pr_events_pull_request_id ON pr_events(pull_request_id);
+CREATE INDEX idx_pr_events_user_id ON pr_events(user_id);
+CREATE INDEX idx_pr_events_previous_user_id ON pr_events(previous_user_id);
+CREATE INDEX idx_pr_events_actor ON pr_events(actor);
+
+CREATE OR REPLACE FUNCTION pr_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'pr_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER pr_events_no_update
+    BEFORE UPDATE OR DELETE ON pr_events
+    FOR EACH ROW EXECUTE FUNCTION pr_events_append_only();
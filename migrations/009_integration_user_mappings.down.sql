@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_integration_user_mappings_user_id;
+
+DROP TABLE IF EXISTS integration_user_mappings;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS integration_user_mappings (
+    provider VARCHAR(20) NOT NULL,
+    login VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, login),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_integration_user_mappings_user_id ON integration_user_mappings(user_id);
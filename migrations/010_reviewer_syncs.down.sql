@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_reviewer_syncs_pull_request_id;
+
+DROP TABLE IF EXISTS reviewer_syncs;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS reviewer_syncs (
+    sync_id BIGSERIAL PRIMARY KEY,
+    provider VARCHAR(20) NOT NULL,
+    pull_request_id VARCHAR(255) NOT NULL,
+    project_id BIGINT NOT NULL,
+    merge_request_iid BIGINT NOT NULL,
+    reviewers TEXT[] NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    pushed_at TIMESTAMPTZ,
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    CHECK (status IN ('PENDING', 'PUSHED', 'FAILED'))
+);
+
+CREATE INDEX idx_reviewer_syncs_pull_request_id ON reviewer_syncs(pull_request_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS team_codeowners;
+
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS changed_files;
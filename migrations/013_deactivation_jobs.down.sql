@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_job_items_due;
+DROP INDEX IF EXISTS idx_job_items_job_id;
+
+DROP TABLE IF EXISTS job_items;
+DROP TABLE IF EXISTS jobs;
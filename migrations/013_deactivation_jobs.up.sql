@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS jobs (
+    job_id BIGSERIAL PRIMARY KEY,
+    job_type VARCHAR(50) NOT NULL,
+    team_name VARCHAR(255) NOT NULL,
+    user_ids TEXT[] NOT NULL DEFAULT '{}',
+    actor VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'RUNNING',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ,
+    CHECK (status IN ('RUNNING', 'COMPLETED'))
+);
+
+CREATE TABLE IF NOT EXISTS job_items (
+    item_id BIGSERIAL PRIMARY KEY,
+    job_id BIGINT NOT NULL,
+    pull_request_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT NOT NULL DEFAULT '',
+    replaced_by VARCHAR(255) NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (job_id) REFERENCES jobs(job_id) ON DELETE CASCADE,
+    CHECK (status IN ('PENDING', 'DONE', 'SKIPPED', 'FAILED'))
+);
+
+CREATE INDEX idx_job_items_job_id ON job_items(job_id);
+CREATE INDEX idx_job_items_due ON job_items(next_attempt_at) WHERE status = 'PENDING';
@@ -0,0 +1,7 @@
+ALTER TABLE pr_reviews
+    ALTER COLUMN submitted_at TYPE TIMESTAMP USING submitted_at AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE pull_requests
+    ALTER COLUMN closed_at TYPE TIMESTAMP USING closed_at AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN merged_at TYPE TIMESTAMP USING merged_at AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE current_setting('TimeZone');
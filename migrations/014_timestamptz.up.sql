@@ -0,0 +1,9 @@
+-- The columns held the wall-clock time of the session time zone, which is
+-- what NOW() and CURRENT_TIMESTAMP wrote into them.
+ALTER TABLE pull_requests
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN merged_at TYPE TIMESTAMPTZ USING merged_at AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN closed_at TYPE TIMESTAMPTZ USING closed_at AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE pr_reviews
+    ALTER COLUMN submitted_at TYPE TIMESTAMPTZ USING submitted_at AT TIME ZONE current_setting('TimeZone');